@@ -1,9 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"transcribe/config"
+	"transcribe/internal/auth"
 	"transcribe/internal/delivery/routes"
+	"transcribe/internal/outbox"
+	"transcribe/internal/progress"
+	"transcribe/internal/queue"
+	"transcribe/internal/search"
+	"transcribe/internal/upload"
+	"transcribe/internal/webhook"
 	"transcribe/pkg/helpers"
 	"transcribe/pkg/logger"
 
@@ -18,7 +26,9 @@ func main() {
 
 	config.InitDB()
 	config.AutoMigrate()
+	config.SeedAdmins()
 	config.InitRedis()
+	config.InitStorage()
 
 	app := fiber.New(fiber.Config{
 		BodyLimit: 100 * 1024 * 1024,
@@ -36,9 +46,24 @@ func main() {
 		},
 	})
 
-	app.Use(cors.New())
+	app.Use(cors.New(cors.Config{
+		ExposeHeaders: "Location,Upload-Offset,Upload-Length,Upload-Expires,Upload-Job-Id,Tus-Resumable,Tus-Version,Tus-Extension,Tus-Max-Size",
+	}))
 
-	routes.SetupRoutes(app)
+	jobQueue := queue.NewRedisQueue()
+
+	go queue.NewReaper(jobQueue).Run(context.Background())
+	go queue.NewScheduler(jobQueue).Run(context.Background())
+	go outbox.NewDispatcher(jobQueue).Run(context.Background())
+	go search.NewIndexer().Run(context.Background())
+	go upload.NewJanitor().Run(context.Background())
+	go auth.NewJanitor().Run(context.Background())
+	go progress.DefaultHub().Run(context.Background())
+	go progress.NewRelay().Run(context.Background())
+	go webhook.NewListener().Run(context.Background())
+	go webhook.NewDispatcher().Run(context.Background())
+
+	routes.SetupRoutes(app, jobQueue)
 
 	port := config.AppConfig.Port
 
@@ -41,8 +41,11 @@ func InitDB() {
 		)
 	}
 
+	// TranslateError turns driver errors such as MySQL 1062 into
+	// gorm.ErrDuplicatedKey, which the repositories check for.
 	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: gormLogger,
+		Logger:         gormLogger,
+		TranslateError: true,
 	})
 
 	if err != nil {
@@ -53,7 +56,7 @@ func InitDB() {
 }
 
 func AutoMigrate() {
-	err := DB.AutoMigrate(&domain.User{}, &domain.TranscriptionJob{})
+	err := DB.AutoMigrate(&domain.User{}, &domain.Workspace{}, &domain.WorkspaceMember{}, &domain.TranscriptionJob{}, &domain.OutboxMessage{}, &domain.TranscriptSegment{}, &domain.Upload{}, &domain.UploadChunk{}, &domain.Webhook{}, &domain.WebhookDelivery{}, &domain.RefreshToken{}, &domain.APIKey{}, &domain.ShareLink{}, &domain.TranscriptRevision{}, &domain.SpeakerProfile{}, &domain.JobSpeaker{}, &domain.JobEvent{})
 
 	if err != nil {
 		logger.Log.Fatal("failed to migrate database:", err)
@@ -61,3 +64,35 @@ func AutoMigrate() {
 
 	logger.Log.Info("database migrated successfully")
 }
+
+// SeedAdmins bootstraps the first admins from ADMIN_EMAILS. Email addresses
+// are not verified at sign-up, so once any admin exists the list is ignored
+// and roles are only changed through the admin API.
+func SeedAdmins() {
+	if len(AppConfig.AdminEmails) == 0 {
+		return
+	}
+
+	var admins int64
+
+	if err := DB.Model(&domain.User{}).Where("role = ?", domain.RoleAdmin).Count(&admins).Error; err != nil {
+		logger.Log.Fatal("failed to seed admin users:", err)
+	}
+
+	if admins > 0 {
+		logger.Log.Info("admin users already exist, ADMIN_EMAILS is ignored")
+		return
+	}
+
+	result := DB.Model(&domain.User{}).
+		Where("email IN ? AND role <> ?", AppConfig.AdminEmails, domain.RoleAdmin).
+		Update("role", domain.RoleAdmin)
+
+	if result.Error != nil {
+		logger.Log.Fatal("failed to seed admin users:", result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		logger.Log.Infof("promoted %d users from ADMIN_EMAILS to admin", result.RowsAffected)
+	}
+}
@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +17,45 @@ type Config struct {
 	Port        string
 	JWTSecret   string
 	UploadDir   string
+	PublicURL   string
+
+	StorageDriver string
+	S3Endpoint    string
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	S3UseSSL      bool
+	S3PathStyle   bool
+
+	StorageSigningKey string
+
+	QueueVisibilityTimeout time.Duration
+	QueueMaxAttempts       int
+	QueueReaperInterval    time.Duration
+	QueueRetryBaseDelay    time.Duration
+	QueueRetryMaxDelay     time.Duration
+	QueueSchedulerInterval time.Duration
+	QueueCancelTimeout     time.Duration
+
+	OutboxPollInterval time.Duration
+	OutboxRetention    time.Duration
+
+	SearchIndexInterval time.Duration
+
+	UploadMaxSize    int64
+	UploadExpiration time.Duration
+
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	WebhookPollInterval   time.Duration
+	WebhookTimeout        time.Duration
+	WebhookMaxAttempts    int
+	WebhookRetryBaseDelay time.Duration
+	WebhookRetryMaxDelay  time.Duration
+
+	AdminEmails []string
 }
 
 var AppConfig *Config
@@ -32,13 +74,61 @@ func LoadConfig() {
 		Port:        getEnv("PORT", ""),
 		JWTSecret:   getEnv("JWT_SECRET", ""),
 		UploadDir:   getEnv("UPLOAD_DIR", ""),
+		PublicURL:   getEnv("PUBLIC_URL", ""),
+
+		StorageDriver: getEnv("STORAGE_DRIVER", "local"),
+		S3Endpoint:    getEnv("S3_ENDPOINT", ""),
+		S3Region:      getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:      getEnv("S3_BUCKET", ""),
+		S3AccessKey:   getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:   getEnv("S3_SECRET_KEY", ""),
+		S3UseSSL:      getEnvBool("S3_USE_SSL", true),
+		S3PathStyle:   getEnvBool("S3_PATH_STYLE", false),
+
+		StorageSigningKey: getEnv("STORAGE_SIGNING_KEY", ""),
+
+		QueueVisibilityTimeout: getEnvDuration("QUEUE_VISIBILITY_TIMEOUT", 10*time.Minute),
+		QueueMaxAttempts:       getEnvInt("QUEUE_MAX_ATTEMPTS", 3),
+		QueueReaperInterval:    getEnvDuration("QUEUE_REAPER_INTERVAL", 30*time.Second),
+		QueueRetryBaseDelay:    getEnvDuration("QUEUE_RETRY_BASE_DELAY", 30*time.Second),
+		QueueRetryMaxDelay:     getEnvDuration("QUEUE_RETRY_MAX_DELAY", 30*time.Minute),
+		QueueSchedulerInterval: getEnvDuration("QUEUE_SCHEDULER_INTERVAL", time.Second),
+		QueueCancelTimeout:     getEnvDuration("QUEUE_CANCEL_TIMEOUT", 30*time.Second),
+
+		OutboxPollInterval: getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxRetention:    getEnvDuration("OUTBOX_RETENTION", 24*time.Hour),
+
+		SearchIndexInterval: getEnvDuration("SEARCH_INDEX_INTERVAL", 10*time.Second),
+
+		UploadMaxSize:    int64(getEnvInt("UPLOAD_MAX_SIZE_MB", 4096)) * 1024 * 1024,
+		UploadExpiration: getEnvDuration("UPLOAD_EXPIRATION", 24*time.Hour),
+
+		AccessTokenTTL:  getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		WebhookPollInterval:   getEnvDuration("WEBHOOK_POLL_INTERVAL", 2*time.Second),
+		WebhookTimeout:        getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		WebhookMaxAttempts:    getEnvInt("WEBHOOK_MAX_ATTEMPTS", 6),
+		WebhookRetryBaseDelay: getEnvDuration("WEBHOOK_RETRY_BASE_DELAY", 30*time.Second),
+		WebhookRetryMaxDelay:  getEnvDuration("WEBHOOK_RETRY_MAX_DELAY", time.Hour),
+
+		AdminEmails: getEnvList("ADMIN_EMAILS"),
 	}
 
 	validateRequired(AppConfig.DatabaseURL, "DATABASE_URL")
 	validateRequired(AppConfig.RedisURL, "REDIS_URL")
 	validateRequired(AppConfig.Port, "PORT")
 	validateRequired(AppConfig.JWTSecret, "JWT_SECRET")
-	validateRequired(AppConfig.UploadDir, "UPLOAD_DIR")
+
+	switch AppConfig.StorageDriver {
+	case "local":
+		validateRequired(AppConfig.UploadDir, "UPLOAD_DIR")
+	case "s3":
+		validateRequired(AppConfig.S3Endpoint, "S3_ENDPOINT")
+		validateRequired(AppConfig.S3Bucket, "S3_BUCKET")
+	default:
+		log.Fatalf("FATAL: environment variable STORAGE_DRIVER must be local or s3")
+	}
 }
 
 func getEnv(key, fallback string) string {
@@ -49,6 +139,66 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+func getEnvList(key string) []string {
+	var values []string
+
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, ok := os.LookupEnv(key)
+
+	if !ok || value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+
+	if err != nil {
+		log.Fatalf("FATAL: environment variable %s must be an integer", key)
+	}
+
+	return parsed
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value, ok := os.LookupEnv(key)
+
+	if !ok || value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseBool(value)
+
+	if err != nil {
+		log.Fatalf("FATAL: environment variable %s must be true or false", key)
+	}
+
+	return parsed
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+
+	if !ok || value == "" {
+		return fallback
+	}
+
+	parsed, err := time.ParseDuration(value)
+
+	if err != nil {
+		log.Fatalf("FATAL: environment variable %s must be a duration (e.g. 30s, 10m)", key)
+	}
+
+	return parsed
+}
+
 func validateRequired(value, key string) {
 	if value == "" {
 		log.Fatalf("FATAL: environment variable %s is not set", key)
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"transcribe/pkg/logger"
+	"transcribe/pkg/storage"
+)
+
+var Storage storage.Storage
+
+func InitStorage() {
+	var err error
+
+	Storage, err = storage.New(storage.Config{
+		Driver:       AppConfig.StorageDriver,
+		LocalDir:     AppConfig.UploadDir,
+		LocalBaseURL: AppConfig.PublicURL,
+		SigningKey:   storageSigningKey(),
+		S3Endpoint:   AppConfig.S3Endpoint,
+		S3Region:     AppConfig.S3Region,
+		S3Bucket:     AppConfig.S3Bucket,
+		S3AccessKey:  AppConfig.S3AccessKey,
+		S3SecretKey:  AppConfig.S3SecretKey,
+		S3UseSSL:     AppConfig.S3UseSSL,
+		S3PathStyle:  AppConfig.S3PathStyle,
+	})
+
+	if err != nil {
+		logger.Log.Fatal("failed to initialize storage: ", err)
+	}
+
+	logger.Log.Infof("storage initialized (%s)", AppConfig.StorageDriver)
+}
+
+// storageSigningKey returns the key for signed file URLs. Without
+// STORAGE_SIGNING_KEY it is derived from JWT_SECRET, so a leaked file URL
+// signature says nothing about the key that signs access tokens.
+func storageSigningKey() string {
+	if AppConfig.StorageSigningKey != "" {
+		return AppConfig.StorageSigningKey
+	}
+
+	key, err := hkdf.Key(sha256.New, []byte(AppConfig.JWTSecret), nil, "transcribe storage url signing", sha256.Size)
+
+	if err != nil {
+		logger.Log.Fatal("failed to derive storage signing key: ", err)
+	}
+
+	return string(key)
+}
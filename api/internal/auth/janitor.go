@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"time"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+)
+
+const janitorInterval = time.Hour
+
+type Janitor struct {
+	tokenRepo *repository.TokenRepository
+	interval  time.Duration
+}
+
+func NewJanitor() *Janitor {
+	return &Janitor{
+		tokenRepo: repository.NewTokenRepository(),
+		interval:  janitorInterval,
+	}
+}
+
+func (j *Janitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	logger.Log.Infof("token janitor started (interval %s)", j.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Log.Info("token janitor stopped")
+			return
+		case <-ticker.C:
+			removed, err := j.tokenRepo.DeleteExpired(time.Now())
+
+			if err != nil {
+				logger.Log.Errorf("failed to delete expired refresh tokens: %v", err)
+				continue
+			}
+
+			if removed > 0 {
+				logger.Log.Infof("removed %d expired refresh tokens", removed)
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package authz
+
+import (
+	"errors"
+	"transcribe/internal/domain"
+	"transcribe/internal/repository"
+)
+
+type Action string
+
+const (
+	ActionView   Action = "view"
+	ActionEdit   Action = "edit"
+	ActionDelete Action = "delete"
+	ActionManage Action = "manage"
+)
+
+var ErrForbidden = errors.New("access denied")
+
+var roleActions = map[string][]Action{
+	domain.WorkspaceRoleViewer: {ActionView},
+	domain.WorkspaceRoleEditor: {ActionView, ActionEdit},
+	domain.WorkspaceRoleOwner:  {ActionView, ActionEdit, ActionDelete, ActionManage},
+}
+
+type Service struct {
+	workspaceRepo *repository.WorkspaceRepository
+}
+
+func NewService() *Service {
+	return &Service{
+		workspaceRepo: repository.NewWorkspaceRepository(),
+	}
+}
+
+func RoleAllows(role string, action Action) bool {
+	for _, allowed := range roleActions[role] {
+		if allowed == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AuthorizeJob reports whether userID may perform action on job. The creator
+// of a job may do anything with it; everyone else needs a workspace role.
+func (s *Service) AuthorizeJob(userID uint, job *domain.TranscriptionJob, action Action) error {
+	if job.UserID == userID {
+		return nil
+	}
+
+	if job.WorkspaceID == nil {
+		return ErrForbidden
+	}
+
+	_, err := s.AuthorizeWorkspace(userID, *job.WorkspaceID, action)
+
+	return err
+}
+
+func (s *Service) AuthorizeWorkspace(userID, workspaceID uint, action Action) (*domain.WorkspaceMember, error) {
+	member, err := s.workspaceRepo.FindMember(workspaceID, userID)
+
+	if errors.Is(err, repository.ErrMemberNotFound) {
+		return nil, ErrForbidden
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !RoleAllows(member.Role, action) {
+		return member, ErrForbidden
+	}
+
+	return member, nil
+}
+
+// JobScope returns the set of jobs userID can view: their own plus every job
+// shared with a workspace they belong to.
+func (s *Service) JobScope(userID uint) (domain.JobScope, error) {
+	workspaceIDs, err := s.workspaceRepo.WorkspaceIDs(userID)
+
+	if err != nil {
+		return domain.JobScope{}, err
+	}
+
+	return domain.JobScope{UserID: userID, WorkspaceIDs: workspaceIDs}, nil
+}
+
+// JobViewers returns every user who can view job: its creator and, when the
+// job is shared with a workspace, all of that workspace's members.
+func (s *Service) JobViewers(job *domain.TranscriptionJob) ([]uint, error) {
+	viewers := []uint{job.UserID}
+
+	if job.WorkspaceID == nil {
+		return viewers, nil
+	}
+
+	members, err := s.workspaceRepo.MemberUserIDs(*job.WorkspaceID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range members {
+		if id != job.UserID {
+			viewers = append(viewers, id)
+		}
+	}
+
+	return viewers, nil
+}
@@ -0,0 +1,418 @@
+package http
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"transcribe/internal/domain"
+	"transcribe/internal/queue"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type AdminHandler struct {
+	userRepo          *repository.UserRepository
+	tokenRepo         *repository.TokenRepository
+	transcriptionRepo *repository.TranscriptionRepository
+	segmentRepo       *repository.SegmentRepository
+	jobQueue          queue.Queue
+}
+
+func NewAdminHandler(jobQueue queue.Queue) *AdminHandler {
+	return &AdminHandler{
+		userRepo:          repository.NewUserRepository(),
+		tokenRepo:         repository.NewTokenRepository(),
+		transcriptionRepo: repository.NewTranscriptionRepository(),
+		segmentRepo:       repository.NewSegmentRepository(),
+		jobQueue:          jobQueue,
+	}
+}
+
+func (h *AdminHandler) ListUsers(c *fiber.Ctx) error {
+	log := logger.Log.WithField("user_id", c.Locals("user_id"))
+
+	page, pageSize := pagination(c)
+
+	filter := domain.UserFilter{
+		Query: strings.TrimSpace(c.Query("q")),
+		Role:  c.Query("role"),
+	}
+
+	if disabled := c.Query("disabled"); disabled != "" {
+		value := disabled == "true"
+		filter.Disabled = &value
+	}
+
+	users, total, err := h.userRepo.GetAll(filter, page, pageSize)
+
+	if err != nil {
+		log.Errorf("failed to list users: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to retrieve users",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"users": users,
+		"pagination": fiber.Map{
+			"page":       page,
+			"page_size":  pageSize,
+			"total":      total,
+			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+func (h *AdminHandler) GetUser(c *fiber.Ctx) error {
+	user, ok := h.findUser(c)
+
+	if !ok {
+		return nil
+	}
+
+	return c.JSON(fiber.Map{
+		"user": user,
+	})
+}
+
+func (h *AdminHandler) DisableUser(c *fiber.Ctx) error {
+	user, ok := h.findUser(c)
+
+	if !ok {
+		return nil
+	}
+
+	log := h.logger(c, user)
+
+	if user.ID == c.Locals("user_id").(uint) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "you cannot disable your own account",
+		})
+	}
+
+	if err := h.userRepo.Update(user.ID, map[string]interface{}{"disabled_at": time.Now()}); err != nil {
+		log.Errorf("failed to disable user: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to disable user",
+		})
+	}
+
+	h.revokeSessions(c.Context(), user.ID, log)
+
+	log.Info("user disabled")
+
+	return c.JSON(fiber.Map{
+		"message": "user disabled successfully",
+	})
+}
+
+func (h *AdminHandler) EnableUser(c *fiber.Ctx) error {
+	user, ok := h.findUser(c)
+
+	if !ok {
+		return nil
+	}
+
+	log := h.logger(c, user)
+
+	if err := h.userRepo.Update(user.ID, map[string]interface{}{"disabled_at": nil}); err != nil {
+		log.Errorf("failed to enable user: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to enable user",
+		})
+	}
+
+	log.Info("user enabled")
+
+	return c.JSON(fiber.Map{
+		"message": "user enabled successfully",
+	})
+}
+
+func (h *AdminHandler) UpdateUserRole(c *fiber.Ctx) error {
+	user, ok := h.findUser(c)
+
+	if !ok {
+		return nil
+	}
+
+	log := h.logger(c, user)
+
+	req := new(domain.UpdateRoleRequest)
+
+	if err := c.BodyParser(req); err != nil || !slices.Contains(domain.Roles, req.Role) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid role. Allowed: " + strings.Join(domain.Roles, ", "),
+		})
+	}
+
+	if user.ID == c.Locals("user_id").(uint) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "you cannot change your own role",
+		})
+	}
+
+	if err := h.userRepo.Update(user.ID, map[string]interface{}{"role": req.Role}); err != nil {
+		log.Errorf("failed to update user role: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to update user role",
+		})
+	}
+
+	h.revokeSessions(c.Context(), user.ID, log)
+
+	log.Infof("user role changed from %s to %s", user.Role, req.Role)
+
+	return c.JSON(fiber.Map{
+		"message": "user role updated successfully",
+		"role":    req.Role,
+	})
+}
+
+func (h *AdminHandler) DeleteUser(c *fiber.Ctx) error {
+	user, ok := h.findUser(c)
+
+	if !ok {
+		return nil
+	}
+
+	log := h.logger(c, user)
+
+	if user.ID == c.Locals("user_id").(uint) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "you cannot delete your own account",
+		})
+	}
+
+	if err := h.userRepo.Delete(user.ID); err != nil {
+		log.Errorf("failed to delete user: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to delete user",
+		})
+	}
+
+	h.revokeSessions(c.Context(), user.ID, log)
+
+	log.Info("user deleted")
+
+	return c.JSON(fiber.Map{
+		"message": "user deleted successfully",
+	})
+}
+
+func (h *AdminHandler) ListJobs(c *fiber.Ctx) error {
+	log := logger.Log.WithField("user_id", c.Locals("user_id"))
+
+	page, pageSize := pagination(c)
+
+	filter := domain.JobFilter{
+		Status:   domain.JobStatus(c.Query("status")),
+		FileName: strings.TrimSpace(c.Query("file_name")),
+	}
+
+	if filter.Status != "" && !filter.Status.Valid() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid status",
+		})
+	}
+
+	if userID, err := strconv.ParseUint(c.Query("user_id"), 10, 64); err == nil {
+		filter.UserID = uint(userID)
+	}
+
+	for param, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(param)
+
+		if value == "" {
+			continue
+		}
+
+		t, err := parseDateParam(value)
+
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid " + param + ". Use RFC 3339 or YYYY-MM-DD",
+			})
+		}
+
+		*target = &t
+	}
+
+	jobs, total, err := h.transcriptionRepo.FindAll(filter, page, pageSize)
+
+	if err != nil {
+		log.Errorf("failed to list jobs: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to retrieve jobs",
+		})
+	}
+
+	responses := make([]fiber.Map, 0, len(jobs))
+
+	for _, job := range jobs {
+		responses = append(responses, fiber.Map{
+			"job_id":        job.ID,
+			"user_id":       job.UserID,
+			"status":        job.Status,
+			"file_name":     job.FileName,
+			"file_size":     job.FileSize,
+			"duration":      job.Duration,
+			"attempts":      job.Attempts,
+			"max_attempts":  job.MaxAttempts,
+			"error_message": job.ErrorMsg,
+			"created_at":    job.CreatedAt,
+			"completed_at":  job.CompletedAt,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"jobs": responses,
+		"pagination": fiber.Map{
+			"page":       page,
+			"page_size":  pageSize,
+			"total":      total,
+			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+func (h *AdminHandler) CancelJob(c *fiber.Ctx) error {
+	jobID := c.Params("job_id")
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"admin_id": c.Locals("user_id"),
+		"job_id":   jobID,
+	})
+
+	job, err := h.transcriptionRepo.FindByID(jobID)
+
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "job not found",
+		})
+	}
+
+	if job.Status.IsTerminal() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "job is already finished or cancelled",
+		})
+	}
+
+	adminID := c.Locals("user_id").(uint)
+
+	event := &domain.JobEvent{
+		Actor:   domain.JobEventActorAdmin,
+		ActorID: &adminID,
+		Message: "cancelled by admin",
+	}
+
+	if err := cancelJob(context.Background(), h.transcriptionRepo, h.jobQueue, job, event, log); err != nil {
+		return writeTransitionError(c, err, "failed to cancel job", log)
+	}
+
+	log.Warn("job force-cancelled by admin")
+
+	return c.JSON(fiber.Map{
+		"message": "job cancellation request sent",
+		"status":  job.Status,
+	})
+}
+
+func (h *AdminHandler) DeleteJob(c *fiber.Ctx) error {
+	jobID := c.Params("job_id")
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"admin_id": c.Locals("user_id"),
+		"job_id":   jobID,
+	})
+
+	job, err := h.transcriptionRepo.FindByID(jobID)
+
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "job not found",
+		})
+	}
+
+	if err := deleteJob(h.transcriptionRepo, h.segmentRepo, job, log); err != nil {
+		log.Errorf("failed to delete job from db: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to delete job",
+		})
+	}
+
+	log.Warn("job deleted by admin")
+
+	return c.JSON(fiber.Map{
+		"message": "job deleted successfully",
+	})
+}
+
+func (h *AdminHandler) findUser(c *fiber.Ctx) (*domain.User, bool) {
+	userID, err := strconv.ParseUint(c.Params("user_id"), 10, 64)
+
+	if err == nil {
+		if user, err := h.userRepo.FindByID(uint(userID)); err == nil {
+			return user, true
+		}
+	}
+
+	c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		"error": "user not found",
+	})
+
+	return nil, false
+}
+
+func (h *AdminHandler) logger(c *fiber.Ctx, user *domain.User) *logrus.Entry {
+	return logger.Log.WithFields(logrus.Fields{
+		"admin_id": c.Locals("user_id"),
+		"user_id":  user.ID,
+	})
+}
+
+func (h *AdminHandler) revokeSessions(ctx context.Context, userID uint, log *logrus.Entry) {
+	tokens, err := h.tokenRepo.RevokeAllForUser(userID)
+
+	if err != nil {
+		log.Errorf("failed to revoke user sessions: %v", err)
+		return
+	}
+
+	revokeAccessTokens(ctx, tokens, log)
+}
+
+func pagination(c *fiber.Ctx) (int, int) {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	return page, pageSize
+}
+
+func parseDateParam(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse("2006-01-02", value)
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"transcribe/internal/domain"
+	"transcribe/internal/repository"
+	"transcribe/pkg/helpers"
+	"transcribe/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const apiKeyDisplayPrefix = 11
+
+type APIKeyHandler struct {
+	apiKeyRepo *repository.APIKeyRepository
+}
+
+func NewAPIKeyHandler() *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyRepo: repository.NewAPIKeyRepository(),
+	}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	req := new(domain.CreateAPIKeyRequest)
+
+	log := logger.Log.WithField("user_id", userID)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warnf("invalid request body: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+
+	if req.Name == "" || len(req.Name) > 100 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "name is required and must be at most 100 characters",
+		})
+	}
+
+	scopes, err := normalizeScopes(req.Scopes)
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "expires_at must be in the future",
+		})
+	}
+
+	rawKey, hash, err := helpers.GenerateAPIKey(domain.APIKeyPrefix)
+
+	if err != nil {
+		log.Errorf("failed to generate api key: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to create api key",
+		})
+	}
+
+	key := &domain.APIKey{
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    rawKey[:apiKeyDisplayPrefix],
+		KeyHash:   hash,
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	if err := h.apiKeyRepo.Create(key); err != nil {
+		log.Errorf("failed to create api key: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to create api key",
+		})
+	}
+
+	log.WithField("api_key_id", key.ID).Info("api key created")
+
+	resp := apiKeyResponse(key)
+	resp.Key = rawKey
+
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	keys, err := h.apiKeyRepo.FindByUserID(userID)
+
+	if err != nil {
+		logger.Log.WithField("user_id", userID).Errorf("failed to list api keys: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to retrieve api keys",
+		})
+	}
+
+	responses := make([]domain.APIKeyResponse, 0, len(keys))
+
+	for i := range keys {
+		responses = append(responses, apiKeyResponse(&keys[i]))
+	}
+
+	return c.JSON(fiber.Map{
+		"api_keys": responses,
+	})
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id":    userID,
+		"api_key_id": c.Params("key_id"),
+	})
+
+	keyID, err := strconv.ParseUint(c.Params("key_id"), 10, 64)
+
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "api key not found",
+		})
+	}
+
+	key, err := h.apiKeyRepo.FindByID(uint(keyID))
+
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "api key not found",
+		})
+	}
+
+	if key.UserID != userID {
+		log.Warn("access denied for api key")
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "access denied",
+		})
+	}
+
+	if err := h.apiKeyRepo.Revoke(key.ID); err != nil {
+		log.Errorf("failed to revoke api key: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to revoke api key",
+		})
+	}
+
+	log.Info("api key revoked")
+
+	return c.JSON(fiber.Map{
+		"message": "api key revoked successfully",
+	})
+}
+
+func apiKeyResponse(key *domain.APIKey) domain.APIKeyResponse {
+	return domain.APIKeyResponse{
+		APIKey: *key,
+		Scopes: strings.Split(key.Scopes, ","),
+	}
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return []string{domain.ScopeJobsRead}, nil
+	}
+
+	var normalized []string
+
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+
+		if !slices.Contains(domain.APIKeyScopes, scope) {
+			return nil, errors.New("invalid scope. Allowed: " + strings.Join(domain.APIKeyScopes, ", "))
+		}
+
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+
+	return normalized, nil
+}
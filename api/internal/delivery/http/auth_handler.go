@@ -1,21 +1,29 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"time"
+	"transcribe/config"
 	"transcribe/internal/domain"
 	"transcribe/internal/repository"
 	"transcribe/pkg/helpers"
 	"transcribe/pkg/logger"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 )
 
 type AuthHandler struct {
-	userRepo *repository.UserRepository
+	userRepo  *repository.UserRepository
+	tokenRepo *repository.TokenRepository
 }
 
 func NewAuthHandler() *AuthHandler {
 	return &AuthHandler{
-		userRepo: repository.NewUserRepository(),
+		userRepo:  repository.NewUserRepository(),
+		tokenRepo: repository.NewTokenRepository(),
 	}
 }
 
@@ -62,7 +70,7 @@ func (h *AuthHandler) SignUp(c *fiber.Ctx) error {
 
 	log = log.WithField("user_id", user.ID)
 
-	token, err := helpers.GenerateToken(user.ID, user.Email)
+	session, err := h.issueSession(user, uuid.New().String(), nil)
 
 	if err != nil {
 		log.Errorf("failed to generate token: %v", err)
@@ -74,15 +82,7 @@ func (h *AuthHandler) SignUp(c *fiber.Ctx) error {
 
 	log.Info("user registered successfully")
 
-	return c.Status(fiber.StatusCreated).JSON(domain.AuthResponse{
-		Message: "user registered successfully",
-		Token:   token,
-		User: domain.UserProfile{
-			ID:    user.ID,
-			Name:  user.Name,
-			Email: user.Email,
-		},
-	})
+	return c.Status(fiber.StatusCreated).JSON(session.response("user registered successfully", user))
 }
 
 func (h *AuthHandler) SignIn(c *fiber.Ctx) error {
@@ -120,7 +120,15 @@ func (h *AuthHandler) SignIn(c *fiber.Ctx) error {
 		})
 	}
 
-	token, err := helpers.GenerateToken(user.ID, user.Email)
+	if user.DisabledAt != nil {
+		log.Warn("sign in attempt on disabled account")
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "account is disabled",
+		})
+	}
+
+	session, err := h.issueSession(user, uuid.New().String(), nil)
 
 	if err != nil {
 		log.Errorf("failed to generate token: %v", err)
@@ -132,13 +140,218 @@ func (h *AuthHandler) SignIn(c *fiber.Ctx) error {
 
 	log.Info("user signed in successfully")
 
-	return c.JSON(domain.AuthResponse{
-		Message: "signin successfully",
-		Token:   token,
+	return c.JSON(session.response("signin successfully", user))
+}
+
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	req := new(domain.RefreshRequest)
+
+	log := logger.Log.WithField("request_ip", c.IP())
+
+	if err := c.BodyParser(req); err != nil || req.RefreshToken == "" {
+		log.Warn("missing refresh token")
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "refresh_token is required",
+		})
+	}
+
+	current, err := h.tokenRepo.FindByHash(helpers.HashToken(req.RefreshToken))
+
+	if err != nil {
+		log.Warnf("unknown refresh token: %v", err)
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "invalid or expired refresh token",
+		})
+	}
+
+	log = log.WithFields(logrus.Fields{
+		"user_id":   current.UserID,
+		"family_id": current.FamilyID,
+	})
+
+	if current.RevokedAt != nil {
+		h.revokeFamily(c.Context(), current.FamilyID, log)
+
+		log.Warn("refresh token reuse detected, session revoked")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "refresh token reuse detected",
+		})
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		log.Warn("expired refresh token")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "invalid or expired refresh token",
+		})
+	}
+
+	user, err := h.userRepo.FindByID(current.UserID)
+
+	if err != nil || user.DisabledAt != nil {
+		log.Warnf("refresh token owner not found or disabled: %v", err)
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "invalid or expired refresh token",
+		})
+	}
+
+	session, err := h.issueSession(user, current.FamilyID, current)
+
+	if errors.Is(err, repository.ErrTokenAlreadyRotated) {
+		h.revokeFamily(c.Context(), current.FamilyID, log)
+
+		log.Warn("concurrent refresh token reuse detected, session revoked")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "refresh token reuse detected",
+		})
+	}
+
+	if err != nil {
+		log.Errorf("failed to rotate refresh token: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to generate token",
+		})
+	}
+
+	log.Info("tokens refreshed")
+
+	return c.JSON(session.response("token refreshed successfully", user))
+}
+
+func (h *AuthHandler) SignOut(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	req := new(domain.SignOutRequest)
+
+	log := logger.Log.WithField("user_id", userID)
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			log.Warnf("invalid request body: %v", err)
+
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid request body",
+			})
+		}
+	}
+
+	ctx := c.Context()
+
+	jti, _ := c.Locals("token_id").(string)
+	expiresAt, _ := c.Locals("token_expires_at").(time.Time)
+
+	if err := helpers.RevokeAccessToken(ctx, jti, expiresAt); err != nil {
+		log.Errorf("failed to revoke access token: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to sign out",
+		})
+	}
+
+	switch {
+	case req.All:
+		tokens, err := h.tokenRepo.RevokeAllForUser(userID)
+
+		if err != nil {
+			log.Errorf("failed to revoke refresh tokens: %v", err)
+
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to sign out",
+			})
+		}
+
+		revokeAccessTokens(ctx, tokens, log)
+
+	case req.RefreshToken != "":
+		token, err := h.tokenRepo.FindByHash(helpers.HashToken(req.RefreshToken))
+
+		if err == nil && token.UserID == userID {
+			h.revokeFamily(ctx, token.FamilyID, log)
+		}
+	}
+
+	log.Info("user signed out")
+
+	return c.JSON(fiber.Map{
+		"message": "signed out successfully",
+	})
+}
+
+type session struct {
+	accessToken  string
+	refreshToken string
+}
+
+func (s *session) response(message string, user *domain.User) domain.AuthResponse {
+	return domain.AuthResponse{
+		Message:      message,
+		Token:        s.accessToken,
+		RefreshToken: s.refreshToken,
+		ExpiresIn:    int64(config.AppConfig.AccessTokenTTL.Seconds()),
 		User: domain.UserProfile{
 			ID:    user.ID,
 			Name:  user.Name,
 			Email: user.Email,
+			Role:  user.Role,
 		},
-	})
+	}
+}
+
+func (h *AuthHandler) issueSession(user *domain.User, familyID string, rotated *domain.RefreshToken) (*session, error) {
+	accessToken, claims, err := helpers.GenerateToken(user.ID, user.Email, user.Role)
+
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, hash, err := helpers.GenerateRefreshToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	row := &domain.RefreshToken{
+		UserID:          user.ID,
+		FamilyID:        familyID,
+		TokenHash:       hash,
+		AccessTokenID:   claims.ID,
+		AccessExpiresAt: claims.ExpiresAt.Time,
+		ExpiresAt:       time.Now().Add(config.AppConfig.RefreshTokenTTL),
+	}
+
+	if rotated != nil {
+		err = h.tokenRepo.Rotate(rotated, row)
+	} else {
+		err = h.tokenRepo.Create(row)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session{accessToken: accessToken, refreshToken: refreshToken}, nil
+}
+
+func (h *AuthHandler) revokeFamily(ctx context.Context, familyID string, log *logrus.Entry) {
+	tokens, err := h.tokenRepo.RevokeFamily(familyID)
+
+	if err != nil {
+		log.Errorf("failed to revoke refresh token family: %v", err)
+		return
+	}
+
+	revokeAccessTokens(ctx, tokens, log)
+}
+
+func revokeAccessTokens(ctx context.Context, tokens []domain.RefreshToken, log *logrus.Entry) {
+	for _, token := range tokens {
+		if err := helpers.RevokeAccessToken(ctx, token.AccessTokenID, token.AccessExpiresAt); err != nil {
+			log.Errorf("failed to revoke access token: %v", err)
+		}
+	}
 }
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"errors"
+	"mime"
+	"path/filepath"
+	"strconv"
+	"transcribe/config"
+	"transcribe/pkg/logger"
+	"transcribe/pkg/storage"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type FileHandler struct{}
+
+func NewFileHandler() *FileHandler {
+	return &FileHandler{}
+}
+
+func (h *FileHandler) Download(c *fiber.Ctx) error {
+	key := c.Params("*")
+
+	log := logger.Log.WithField("storage_key", key)
+
+	local, ok := config.Storage.(*storage.LocalStorage)
+
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "file not found",
+		})
+	}
+
+	expires, _ := strconv.ParseInt(c.Query("expires"), 10, 64)
+
+	if !local.VerifySignature(key, expires, c.Query("signature")) {
+		log.Warn("invalid or expired file signature")
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "invalid or expired link",
+		})
+	}
+
+	file, err := local.Open(c.Context(), key)
+
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "file not found",
+			})
+		}
+
+		log.Errorf("failed to open file: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to open file",
+		})
+	}
+
+	if contentType := mime.TypeByExtension(filepath.Ext(key)); contentType != "" {
+		c.Set(fiber.HeaderContentType, contentType)
+	}
+
+	return c.SendStream(file)
+}
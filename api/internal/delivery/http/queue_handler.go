@@ -0,0 +1,208 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"transcribe/internal/domain"
+	"transcribe/internal/progress"
+	"transcribe/internal/queue"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type QueueHandler struct {
+	transcriptionRepo *repository.TranscriptionRepository
+	jobQueue          queue.Queue
+}
+
+func NewQueueHandler(jobQueue queue.Queue) *QueueHandler {
+	return &QueueHandler{
+		transcriptionRepo: repository.NewTranscriptionRepository(),
+		jobQueue:          jobQueue,
+	}
+}
+
+func (h *QueueHandler) QueueStats(c *fiber.Ctx) error {
+	log := logger.Log.WithField("user_id", c.Locals("user_id"))
+
+	stats, err := h.jobQueue.Stats(context.Background())
+
+	if err != nil {
+		log.Errorf("failed to fetch queue stats: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to fetch queue stats",
+		})
+	}
+
+	jobs, err := h.transcriptionRepo.CountByStatus()
+
+	if err != nil {
+		log.Errorf("failed to count jobs by status: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to fetch queue stats",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"queue": stats,
+		"jobs":  jobs,
+	})
+}
+
+func (h *QueueHandler) ListDeadJobs(c *fiber.Ctx) error {
+	log := logger.Log.WithField("user_id", c.Locals("user_id"))
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := int64((page - 1) * pageSize)
+
+	jobs, total, err := h.jobQueue.DeadJobs(context.Background(), offset, int64(pageSize))
+
+	if err != nil {
+		log.Errorf("failed to fetch dead jobs: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to fetch dead jobs",
+		})
+	}
+
+	log.Infof("retrieved %d dead jobs", len(jobs))
+
+	return c.JSON(fiber.Map{
+		"jobs": jobs,
+		"pagination": fiber.Map{
+			"page":       page,
+			"page_size":  pageSize,
+			"total":      total,
+			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+func (h *QueueHandler) ReplayDeadJob(c *fiber.Ctx) error {
+	jobID := c.Params("job_id")
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": c.Locals("user_id"),
+		"job_id":  jobID,
+	})
+
+	ctx := context.Background()
+
+	dead, err := h.jobQueue.FindDead(ctx, jobID)
+
+	if err != nil {
+		if errors.Is(err, queue.ErrDeadMissing) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "dead job not found",
+			})
+		}
+
+		log.Errorf("failed to find dead job: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to replay dead job",
+		})
+	}
+
+	// The job must be queued before its payload is, otherwise a worker can
+	// claim it while it is still failed and drop it.
+	err = h.transcriptionRepo.Transition(jobID, domain.JobStatusQueued, map[string]interface{}{
+		"attempts":      0,
+		"error_msg":     "",
+		"next_retry_at": nil,
+		"completed_at":  nil,
+	})
+
+	if err != nil {
+		return writeTransitionError(c, err, "failed to replay dead job", log)
+	}
+
+	if _, err := h.jobQueue.ReplayDead(ctx, jobID); err != nil {
+		log.Errorf("failed to replay dead job: %v", err)
+
+		restore := map[string]interface{}{
+			"attempts":     dead.Job.Attempts,
+			"error_msg":    dead.Error,
+			"completed_at": dead.FailedAt,
+		}
+
+		if err := h.transcriptionRepo.Transition(jobID, domain.JobStatusFailed, restore); err != nil {
+			log.Errorf("failed to restore failed status after unsuccessful replay: %v", err)
+		}
+
+		if errors.Is(err, queue.ErrDeadMissing) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "dead job not found",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to replay dead job",
+		})
+	}
+
+	adminID := c.Locals("user_id").(uint)
+
+	if err := progress.Record(ctx, &domain.JobEvent{
+		JobID:   jobID,
+		Stage:   string(domain.JobStatusQueued),
+		Message: "replayed from dead-letter queue",
+		Actor:   domain.JobEventActorAdmin,
+		ActorID: &adminID,
+	}); err != nil {
+		log.Warnf("failed to record replay event: %v", err)
+	}
+
+	log.Info("dead job replayed")
+
+	return c.JSON(fiber.Map{
+		"message": "job requeued",
+		"job_id":  jobID,
+		"status":  domain.JobStatusQueued,
+	})
+}
+
+func (h *QueueHandler) DiscardDeadJob(c *fiber.Ctx) error {
+	jobID := c.Params("job_id")
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": c.Locals("user_id"),
+		"job_id":  jobID,
+	})
+
+	if err := h.jobQueue.DiscardDead(context.Background(), jobID); err != nil {
+		if errors.Is(err, queue.ErrDeadMissing) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "dead job not found",
+			})
+		}
+
+		log.Errorf("failed to discard dead job: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to discard dead job",
+		})
+	}
+
+	log.Info("dead job discarded")
+
+	return c.JSON(fiber.Map{
+		"message": "dead job discarded",
+	})
+}
@@ -0,0 +1,304 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"transcribe/internal/authz"
+	"transcribe/internal/domain"
+	"transcribe/internal/repository"
+	"transcribe/internal/transcript"
+	"transcribe/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type RevisionHandler struct {
+	transcriptionRepo *repository.TranscriptionRepository
+	revisionRepo      *repository.RevisionRepository
+	speakerRepo       *repository.SpeakerRepository
+	authz             *authz.Service
+}
+
+func NewRevisionHandler() *RevisionHandler {
+	return &RevisionHandler{
+		transcriptionRepo: repository.NewTranscriptionRepository(),
+		revisionRepo:      repository.NewRevisionRepository(),
+		speakerRepo:       repository.NewSpeakerRepository(),
+		authz:             authz.NewService(),
+	}
+}
+
+func (h *RevisionHandler) EditSegments(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	req := new(domain.EditSegmentsRequest)
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": userID,
+		"job_id":  c.Params("job_id"),
+	})
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warnf("invalid request body: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	job, ok := h.findEditableJob(c, log)
+
+	if !ok {
+		return nil
+	}
+
+	if req.Revision != nil && *req.Revision != job.Revision {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":    repository.ErrRevisionConflict.Error(),
+			"revision": job.Revision,
+		})
+	}
+
+	segments, ok := decodeSegments(c, job.Segments, log)
+
+	if !ok {
+		return nil
+	}
+
+	edited, err := transcript.Apply(segments, req.Operations)
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	operations, _ := json.Marshal(req.Operations)
+
+	return h.save(c, job, edited, &domain.TranscriptRevision{
+		AuthorID:   &userID,
+		Action:     domain.RevisionActionEdit,
+		Operations: string(operations),
+	}, log)
+}
+
+func (h *RevisionHandler) ListRevisions(c *fiber.Ctx) error {
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": c.Locals("user_id"),
+		"job_id":  c.Params("job_id"),
+	})
+
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionView, log)
+
+	if !ok {
+		return nil
+	}
+
+	page, pageSize := pagination(c)
+
+	revisions, total, err := h.revisionRepo.FindByJobID(job.ID, page, pageSize)
+
+	if err != nil {
+		log.Errorf("failed to list revisions: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to retrieve revisions",
+		})
+	}
+
+	responses := make([]domain.RevisionResponse, 0, len(revisions))
+
+	for i := range revisions {
+		responses = append(responses, revisionResponse(&revisions[i], false))
+	}
+
+	return c.JSON(fiber.Map{
+		"job_id":    job.ID,
+		"revision":  job.Revision,
+		"revisions": responses,
+		"pagination": fiber.Map{
+			"page":       page,
+			"page_size":  pageSize,
+			"total":      total,
+			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+func (h *RevisionHandler) GetRevision(c *fiber.Ctx) error {
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": c.Locals("user_id"),
+		"job_id":  c.Params("job_id"),
+	})
+
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionView, log)
+
+	if !ok {
+		return nil
+	}
+
+	revision, ok := h.findRevision(c, job)
+
+	if !ok {
+		return nil
+	}
+
+	resp := revisionResponse(revision, true)
+	transcript.RenameSpeakers(resp.Segments, speakerNames(h.speakerRepo, job.ID, log))
+
+	return c.JSON(resp)
+}
+
+func (h *RevisionHandler) RestoreRevision(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": userID,
+		"job_id":  c.Params("job_id"),
+	})
+
+	job, ok := h.findEditableJob(c, log)
+
+	if !ok {
+		return nil
+	}
+
+	revision, ok := h.findRevision(c, job)
+
+	if !ok {
+		return nil
+	}
+
+	if revision.Number == job.Revision {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "revision is already current",
+		})
+	}
+
+	segments, ok := decodeSegments(c, revision.Segments, log)
+
+	if !ok {
+		return nil
+	}
+
+	return h.save(c, job, segments, &domain.TranscriptRevision{
+		AuthorID:     &userID,
+		Action:       domain.RevisionActionRestore,
+		RestoredFrom: &revision.Number,
+	}, log)
+}
+
+func (h *RevisionHandler) findEditableJob(c *fiber.Ctx, log *logrus.Entry) (*domain.TranscriptionJob, bool) {
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionEdit, log)
+
+	if !ok {
+		return nil, false
+	}
+
+	if job.Status != domain.JobStatusDone {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "job is not finished yet",
+		})
+
+		return nil, false
+	}
+
+	return job, true
+}
+
+func (h *RevisionHandler) findRevision(c *fiber.Ctx, job *domain.TranscriptionJob) (*domain.TranscriptRevision, bool) {
+	number, err := strconv.Atoi(c.Params("revision"))
+
+	if err == nil {
+		revision, err := h.revisionRepo.FindByNumber(job.ID, number)
+
+		if err == nil {
+			return revision, true
+		}
+	}
+
+	c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		"error": "revision not found",
+	})
+
+	return nil, false
+}
+
+func (h *RevisionHandler) save(c *fiber.Ctx, job *domain.TranscriptionJob, segments []domain.Segment, rev *domain.TranscriptRevision, log *logrus.Entry) error {
+	encoded, err := json.Marshal(segments)
+
+	if err != nil {
+		log.Errorf("failed to encode segments: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to save transcript",
+		})
+	}
+
+	rev.Segments = string(encoded)
+	rev.Text = transcript.JoinText(segments)
+
+	if err := h.revisionRepo.Save(job, rev); err != nil {
+		if errors.Is(err, repository.ErrRevisionConflict) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Errorf("failed to save transcript revision: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to save transcript",
+		})
+	}
+
+	log.WithField("revision", rev.Number).Infof("transcript %s saved", rev.Action)
+
+	transcript.RenameSpeakers(segments, speakerNames(h.speakerRepo, job.ID, log))
+
+	return c.JSON(domain.TranscriptionResponse{
+		JobID:    job.ID,
+		Status:   job.Status,
+		Revision: rev.Number,
+		Text:     rev.Text,
+		Segments: segments,
+	})
+}
+
+func decodeSegments(c *fiber.Ctx, raw string, log *logrus.Entry) ([]domain.Segment, bool) {
+	var segments []domain.Segment
+
+	if raw == "" {
+		return segments, true
+	}
+
+	if err := json.Unmarshal([]byte(raw), &segments); err != nil {
+		log.Errorf("failed to decode segments: %v", err)
+
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to read transcription segments",
+		})
+
+		return nil, false
+	}
+
+	return segments, true
+}
+
+func revisionResponse(revision *domain.TranscriptRevision, full bool) domain.RevisionResponse {
+	resp := domain.RevisionResponse{
+		TranscriptRevision: *revision,
+	}
+
+	if revision.Operations != "" {
+		json.Unmarshal([]byte(revision.Operations), &resp.Operations)
+	}
+
+	if full {
+		resp.Text = revision.Text
+		json.Unmarshal([]byte(revision.Segments), &resp.Segments)
+	}
+
+	return resp
+}
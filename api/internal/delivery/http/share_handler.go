@@ -0,0 +1,428 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strconv"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/authz"
+	"transcribe/internal/domain"
+	"transcribe/internal/repository"
+	"transcribe/internal/transcript"
+	"transcribe/pkg/helpers"
+	"transcribe/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	shareDisplayPrefix    = 11
+	sharePasswordAttempts = 10
+	sharePasswordWindow   = 15 * time.Minute
+)
+
+type ShareHandler struct {
+	shareRepo         *repository.ShareRepository
+	speakerRepo       *repository.SpeakerRepository
+	transcriptionRepo *repository.TranscriptionRepository
+	authz             *authz.Service
+}
+
+func NewShareHandler() *ShareHandler {
+	return &ShareHandler{
+		shareRepo:         repository.NewShareRepository(),
+		speakerRepo:       repository.NewSpeakerRepository(),
+		transcriptionRepo: repository.NewTranscriptionRepository(),
+		authz:             authz.NewService(),
+	}
+}
+
+func (h *ShareHandler) CreateShareLink(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	req := new(domain.CreateShareLinkRequest)
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": userID,
+		"job_id":  c.Params("job_id"),
+	})
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			log.Warnf("invalid request body: %v", err)
+
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid request body",
+			})
+		}
+	}
+
+	if req.Password != "" && len(req.Password) < 6 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "password must be at least 6 characters",
+		})
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "expires_at must be in the future",
+		})
+	}
+
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionManage, log)
+
+	if !ok {
+		return nil
+	}
+
+	if job.Status != domain.JobStatusDone {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "job is not finished yet",
+		})
+	}
+
+	token, hash, err := helpers.GenerateAPIKey(domain.ShareTokenPrefix)
+
+	if err != nil {
+		log.Errorf("failed to generate share token: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to create share link",
+		})
+	}
+
+	link := &domain.ShareLink{
+		JobID:      job.ID,
+		CreatedBy:  userID,
+		Prefix:     token[:shareDisplayPrefix],
+		TokenHash:  hash,
+		AllowAudio: req.AllowAudio,
+		ExpiresAt:  req.ExpiresAt,
+	}
+
+	if req.Password != "" {
+		passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+
+		if err != nil {
+			log.Errorf("failed to hash share password: %v", err)
+
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to create share link",
+			})
+		}
+
+		link.PasswordHash = string(passwordHash)
+	}
+
+	if err := h.shareRepo.Create(link); err != nil {
+		log.Errorf("failed to create share link: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to create share link",
+		})
+	}
+
+	log.WithField("share_id", link.ID).Info("share link created")
+
+	resp := shareLinkResponse(link)
+	resp.Token = token
+	resp.URL = c.BaseURL() + "/api/public/share/" + token
+
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+func (h *ShareHandler) ListShareLinks(c *fiber.Ctx) error {
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": c.Locals("user_id"),
+		"job_id":  c.Params("job_id"),
+	})
+
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionManage, log)
+
+	if !ok {
+		return nil
+	}
+
+	links, err := h.shareRepo.FindByJobID(job.ID)
+
+	if err != nil {
+		log.Errorf("failed to list share links: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to retrieve share links",
+		})
+	}
+
+	responses := make([]domain.ShareLinkResponse, 0, len(links))
+
+	for i := range links {
+		responses = append(responses, shareLinkResponse(&links[i]))
+	}
+
+	return c.JSON(fiber.Map{
+		"share_links": responses,
+	})
+}
+
+func (h *ShareHandler) RevokeShareLink(c *fiber.Ctx) error {
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id":  c.Locals("user_id"),
+		"job_id":   c.Params("job_id"),
+		"share_id": c.Params("share_id"),
+	})
+
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionManage, log)
+
+	if !ok {
+		return nil
+	}
+
+	shareID, err := strconv.ParseUint(c.Params("share_id"), 10, 64)
+
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "share link not found",
+		})
+	}
+
+	link, err := h.shareRepo.FindByID(uint(shareID))
+
+	if err != nil || link.JobID != job.ID {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "share link not found",
+		})
+	}
+
+	if err := h.shareRepo.Revoke(link.ID); err != nil {
+		log.Errorf("failed to revoke share link: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to revoke share link",
+		})
+	}
+
+	log.Info("share link revoked")
+
+	return c.JSON(fiber.Map{
+		"message": "share link revoked successfully",
+	})
+}
+
+func (h *ShareHandler) GetSharedTranscript(c *fiber.Ctx) error {
+	link, job, ok := h.openShare(c)
+
+	if !ok {
+		return nil
+	}
+
+	var segments []domain.Segment
+
+	if job.Segments != "" {
+		if err := json.Unmarshal([]byte(job.Segments), &segments); err != nil {
+			logger.Log.WithField("job_id", job.ID).Errorf("failed to decode shared segments: %v", err)
+
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to read transcription segments",
+			})
+		}
+	}
+
+	transcript.RenameSpeakers(segments, speakerNames(h.speakerRepo, job.ID, logger.Log.WithField("job_id", job.ID)))
+
+	if err := h.shareRepo.RecordAccess(link.ID, time.Now()); err != nil {
+		logger.Log.WithField("share_id", link.ID).Warnf("failed to record share access: %v", err)
+	}
+
+	shared := domain.SharedTranscript{
+		FileName:    job.FileName,
+		Duration:    job.Duration,
+		Language:    job.Options.Language,
+		Text:        job.Text,
+		Segments:    segments,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+		ExpiresAt:   link.ExpiresAt,
+	}
+
+	if link.AllowAudio {
+		shared.AudioURL = c.BaseURL() + "/api/public/share/" + c.Params("token") + "/audio"
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return c.JSON(shared)
+}
+
+func (h *ShareHandler) StreamSharedAudio(c *fiber.Ctx) error {
+	link, job, ok := h.openShare(c)
+
+	if !ok {
+		return nil
+	}
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"share_id": link.ID,
+		"job_id":   job.ID,
+	})
+
+	if !link.AllowAudio {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "audio is not shared",
+		})
+	}
+
+	info, err := config.Storage.Stat(c.Context(), job.FilePath)
+
+	if err != nil {
+		log.Errorf("failed to stat shared audio: %v", err)
+
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "file not found",
+		})
+	}
+
+	file, err := config.Storage.Open(c.Context(), job.FilePath)
+
+	if err != nil {
+		log.Errorf("failed to open shared audio: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to open file",
+		})
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(job.FileName))
+
+	if contentType == "" {
+		contentType = info.ContentType
+	}
+
+	if contentType != "" {
+		c.Set(fiber.HeaderContentType, contentType)
+	}
+
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", job.FileName))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return c.SendStream(file, int(info.Size))
+}
+
+// openShare resolves the token in the path and checks expiry, revocation and
+// the optional password, see sharePassword.
+func (h *ShareHandler) openShare(c *fiber.Ctx) (*domain.ShareLink, *domain.TranscriptionJob, bool) {
+	log := logger.Log.WithField("request_ip", c.IP())
+
+	link, err := h.shareRepo.FindByHash(helpers.HashToken(c.Params("token")))
+
+	if err != nil {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "share link not found",
+		})
+
+		return nil, nil, false
+	}
+
+	log = log.WithField("share_id", link.ID)
+
+	if link.RevokedAt != nil || (link.ExpiresAt != nil && time.Now().After(*link.ExpiresAt)) {
+		c.Status(fiber.StatusGone).JSON(fiber.Map{
+			"error": "share link has expired or been revoked",
+		})
+
+		return nil, nil, false
+	}
+
+	if link.PasswordHash != "" && !h.checkPassword(c, link, log) {
+		return nil, nil, false
+	}
+
+	job, err := h.transcriptionRepo.FindByID(link.JobID)
+
+	if err != nil {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "share link not found",
+		})
+
+		return nil, nil, false
+	}
+
+	return link, job, true
+}
+
+func (h *ShareHandler) checkPassword(c *fiber.Ctx, link *domain.ShareLink, log *logrus.Entry) bool {
+	password := sharePassword(c)
+
+	if password == "" {
+		c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "password required",
+		})
+
+		return false
+	}
+
+	attemptsKey := fmt.Sprintf("share_attempts:%d", link.ID)
+
+	attempts, err := config.RedisClient.Get(c.Context(), attemptsKey).Int()
+
+	if err == nil && attempts >= sharePasswordAttempts {
+		log.Warn("share link locked after failed password attempts")
+
+		c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"error": "too many failed attempts, try again later",
+		})
+
+		return false
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)) != nil {
+		pipe := config.RedisClient.TxPipeline()
+		pipe.Incr(c.Context(), attemptsKey)
+		pipe.Expire(c.Context(), attemptsKey, sharePasswordWindow)
+
+		if _, err := pipe.Exec(c.Context()); err != nil {
+			log.Warnf("failed to record failed share password attempt: %v", err)
+		}
+
+		log.Warn("invalid share password")
+
+		c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "invalid password",
+		})
+
+		return false
+	}
+
+	return true
+}
+
+func shareLinkResponse(link *domain.ShareLink) domain.ShareLinkResponse {
+	return domain.ShareLinkResponse{
+		ShareLink:   *link,
+		HasPassword: link.PasswordHash != "",
+	}
+}
+
+// sharePassword reads the password from X-Share-Password or, on POST, from a
+// JSON or form body. It is never taken from the query string, where it would
+// end up in access logs, browser history and Referer headers.
+func sharePassword(c *fiber.Ctx) string {
+	if password := c.Get("X-Share-Password"); password != "" {
+		return password
+	}
+
+	if c.Method() != fiber.MethodPost {
+		return ""
+	}
+
+	req := new(domain.SharePasswordRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		return ""
+	}
+
+	return req.Password
+}
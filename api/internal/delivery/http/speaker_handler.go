@@ -0,0 +1,434 @@
+package http
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"transcribe/internal/authz"
+	"transcribe/internal/domain"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type SpeakerHandler struct {
+	speakerRepo       *repository.SpeakerRepository
+	transcriptionRepo *repository.TranscriptionRepository
+	authz             *authz.Service
+}
+
+func NewSpeakerHandler() *SpeakerHandler {
+	return &SpeakerHandler{
+		speakerRepo:       repository.NewSpeakerRepository(),
+		transcriptionRepo: repository.NewTranscriptionRepository(),
+		authz:             authz.NewService(),
+	}
+}
+
+func (h *SpeakerHandler) CreateProfile(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	req := new(domain.SpeakerProfileRequest)
+
+	log := logger.Log.WithField("user_id", userID)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warnf("invalid request body: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := normalizeSpeakerProfile(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	profile := &domain.SpeakerProfile{
+		UserID: userID,
+		Name:   req.Name,
+		Notes:  req.Notes,
+	}
+
+	if err := h.speakerRepo.CreateProfile(profile); err != nil {
+		if errors.Is(err, repository.ErrSpeakerProfileExists) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Errorf("failed to create speaker profile: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to create speaker profile",
+		})
+	}
+
+	log.WithField("profile_id", profile.ID).Info("speaker profile created")
+
+	return c.Status(fiber.StatusCreated).JSON(profile)
+}
+
+func (h *SpeakerHandler) ListProfiles(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	profiles, err := h.speakerRepo.FindProfilesByUserID(userID)
+
+	if err != nil {
+		logger.Log.WithField("user_id", userID).Errorf("failed to list speaker profiles: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to retrieve speaker profiles",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"profiles": profiles,
+	})
+}
+
+func (h *SpeakerHandler) UpdateProfile(c *fiber.Ctx) error {
+	profile, ok := h.findOwnedProfile(c)
+
+	if !ok {
+		return nil
+	}
+
+	req := new(domain.SpeakerProfileRequest)
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id":    profile.UserID,
+		"profile_id": profile.ID,
+	})
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warnf("invalid request body: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := normalizeSpeakerProfile(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	profile.Name = req.Name
+	profile.Notes = req.Notes
+
+	if err := h.speakerRepo.UpdateProfile(profile); err != nil {
+		if errors.Is(err, repository.ErrSpeakerProfileExists) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Errorf("failed to update speaker profile: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to update speaker profile",
+		})
+	}
+
+	log.Info("speaker profile updated")
+
+	return c.JSON(profile)
+}
+
+func (h *SpeakerHandler) DeleteProfile(c *fiber.Ctx) error {
+	profile, ok := h.findOwnedProfile(c)
+
+	if !ok {
+		return nil
+	}
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id":    profile.UserID,
+		"profile_id": profile.ID,
+	})
+
+	if err := h.speakerRepo.DeleteProfile(profile.ID); err != nil {
+		log.Errorf("failed to delete speaker profile: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to delete speaker profile",
+		})
+	}
+
+	log.Info("speaker profile deleted")
+
+	return c.JSON(fiber.Map{
+		"message": "speaker profile deleted successfully",
+	})
+}
+
+func (h *SpeakerHandler) GetJobSpeakers(c *fiber.Ctx) error {
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": c.Locals("user_id"),
+		"job_id":  c.Params("job_id"),
+	})
+
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionView, log)
+
+	if !ok {
+		return nil
+	}
+
+	segments, ok := decodeSegments(c, job.Segments, log)
+
+	if !ok {
+		return nil
+	}
+
+	mapped, err := h.speakerRepo.FindByJobID(job.ID)
+
+	if err != nil {
+		log.Errorf("failed to fetch job speakers: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to retrieve speakers",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"job_id":   job.ID,
+		"speakers": jobSpeakerResponses(segments, mapped),
+	})
+}
+
+// UpdateJobSpeakers replaces the job's speaker map. Labels left out of the
+// request, or sent without a name or profile, fall back to the raw label.
+func (h *SpeakerHandler) UpdateJobSpeakers(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	req := new(domain.UpdateJobSpeakersRequest)
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": userID,
+		"job_id":  c.Params("job_id"),
+	})
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warnf("invalid request body: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionEdit, log)
+
+	if !ok {
+		return nil
+	}
+
+	if job.Status != domain.JobStatusDone {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "job is not finished yet",
+		})
+	}
+
+	segments, ok := decodeSegments(c, job.Segments, log)
+
+	if !ok {
+		return nil
+	}
+
+	labels := make(map[string]bool)
+
+	for _, seg := range segments {
+		labels[seg.Speaker] = true
+	}
+
+	profiles, err := h.assignedProfiles(userID, req.Speakers)
+
+	if err != nil {
+		if errors.Is(err, repository.ErrSpeakerProfileNotFound) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Errorf("failed to fetch speaker profiles: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to update speakers",
+		})
+	}
+
+	var speakers []domain.JobSpeaker
+
+	for label, assignment := range req.Speakers {
+		if label == "" || !labels[label] {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "unknown speaker label: " + label,
+			})
+		}
+
+		name := strings.TrimSpace(assignment.Name)
+
+		if assignment.ProfileID != nil {
+			name = profiles[*assignment.ProfileID].Name
+		}
+
+		if len(name) > 100 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "speaker name must be at most 100 characters",
+			})
+		}
+
+		if name == "" {
+			continue
+		}
+
+		speakers = append(speakers, domain.JobSpeaker{
+			JobID:     job.ID,
+			Label:     label,
+			Name:      name,
+			ProfileID: assignment.ProfileID,
+		})
+	}
+
+	if err := h.speakerRepo.ReplaceForJob(job.ID, speakers); err != nil {
+		log.Errorf("failed to save job speakers: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to update speakers",
+		})
+	}
+
+	log.Infof("speaker map updated with %d names", len(speakers))
+
+	return c.JSON(fiber.Map{
+		"job_id":   job.ID,
+		"speakers": jobSpeakerResponses(segments, speakers),
+	})
+}
+
+func (h *SpeakerHandler) assignedProfiles(userID uint, assignments map[string]domain.SpeakerAssignment) (map[uint]domain.SpeakerProfile, error) {
+	var ids []uint
+
+	for _, assignment := range assignments {
+		if assignment.ProfileID != nil {
+			ids = append(ids, *assignment.ProfileID)
+		}
+	}
+
+	profiles := make(map[uint]domain.SpeakerProfile)
+
+	if len(ids) == 0 {
+		return profiles, nil
+	}
+
+	found, err := h.speakerRepo.FindProfilesByIDs(ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range found {
+		if profile.UserID == userID {
+			profiles[profile.ID] = profile
+		}
+	}
+
+	for _, id := range ids {
+		if _, ok := profiles[id]; !ok {
+			return nil, repository.ErrSpeakerProfileNotFound
+		}
+	}
+
+	return profiles, nil
+}
+
+func (h *SpeakerHandler) findOwnedProfile(c *fiber.Ctx) (*domain.SpeakerProfile, bool) {
+	userID := c.Locals("user_id").(uint)
+
+	profileID, err := strconv.ParseUint(c.Params("profile_id"), 10, 64)
+
+	if err == nil {
+		profile, err := h.speakerRepo.FindProfile(uint(profileID))
+
+		if err == nil && profile.UserID == userID {
+			return profile, true
+		}
+	}
+
+	c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		"error": "speaker profile not found",
+	})
+
+	return nil, false
+}
+
+// speakerNames loads the speaker map of a job for display. Failures are only
+// logged so responses fall back to raw labels.
+func speakerNames(speakerRepo *repository.SpeakerRepository, jobID string, log *logrus.Entry) map[string]string {
+	names, err := speakerRepo.NamesForJobs([]string{jobID})
+
+	if err != nil {
+		log.Warnf("failed to load speaker names: %v", err)
+		return nil
+	}
+
+	return names[jobID]
+}
+
+func jobSpeakerResponses(segments []domain.Segment, mapped []domain.JobSpeaker) []domain.JobSpeakerResponse {
+	byLabel := make(map[string]*domain.JobSpeakerResponse)
+
+	for _, seg := range segments {
+		if seg.Speaker == "" {
+			continue
+		}
+
+		resp, ok := byLabel[seg.Speaker]
+
+		if !ok {
+			resp = &domain.JobSpeakerResponse{Label: seg.Speaker}
+			byLabel[seg.Speaker] = resp
+		}
+
+		resp.Segments++
+		resp.Duration += seg.EndTime - seg.StartTime
+	}
+
+	for _, speaker := range mapped {
+		if resp, ok := byLabel[speaker.Label]; ok {
+			resp.Name = speaker.Name
+			resp.ProfileID = speaker.ProfileID
+		}
+	}
+
+	responses := make([]domain.JobSpeakerResponse, 0, len(byLabel))
+
+	for _, resp := range byLabel {
+		responses = append(responses, *resp)
+	}
+
+	sort.Slice(responses, func(i, j int) bool {
+		return responses[i].Label < responses[j].Label
+	})
+
+	return responses
+}
+
+func normalizeSpeakerProfile(req *domain.SpeakerProfileRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Notes = strings.TrimSpace(req.Notes)
+
+	if req.Name == "" || len(req.Name) > 100 {
+		return errors.New("name is required and must be at most 100 characters")
+	}
+
+	if len(req.Notes) > 255 {
+		return errors.New("notes must be at most 255 characters")
+	}
+
+	return nil
+}
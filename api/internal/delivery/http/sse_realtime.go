@@ -0,0 +1,205 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"transcribe/internal/authz"
+	"transcribe/internal/progress"
+	"transcribe/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	sseHeartbeatInterval = 15 * time.Second
+	sseRetry             = 3 * time.Second
+
+	// sseSnapshotID is sent with the current status when a job has no
+	// retained history. Resuming from it replays every later event.
+	sseSnapshotID = "0-0"
+)
+
+// StreamEvents serves job progress as server-sent events. Event ids are job
+// stream entry ids, so a client resuming with Last-Event-ID gets every event
+// it missed before the live tail.
+func (h *RealtimeHandler) StreamEvents(c *fiber.Ctx) error {
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": c.Locals("user_id"),
+		"job_id":  c.Params("job_id"),
+	})
+
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionView, log)
+
+	if !ok {
+		return nil
+	}
+
+	lastEventID := c.Get("Last-Event-ID", c.Query("last_event_id"))
+
+	if lastEventID != "" && !progress.ValidEventID(lastEventID) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid last event id",
+		})
+	}
+
+	sub, history, err := progress.Follow(context.Background(), job.ID, lastEventID)
+
+	if err != nil {
+		log.Errorf("failed to follow job progress: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to subscribe to job progress",
+		})
+	}
+
+	// Re-read the job now that the subscription is live so no update falls
+	// between the snapshot and the stream.
+	job, err = h.transcriptionRepo.FindByID(job.ID)
+
+	if err != nil {
+		sub.Close()
+		log.Errorf("failed to fetch job: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to retrieve job",
+		})
+	}
+
+	// 204 tells EventSource not to reconnect once the client has seen the end.
+	if job.Status.IsTerminal() && lastEventID != "" && len(history) == 0 {
+		sub.Close()
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	var snapshot []byte
+
+	if lastEventID == "" && len(history) == 0 {
+		snapshot, _ = json.Marshal(progress.Message{
+			JobID:     job.ID,
+			Status:    string(job.Status),
+			Timestamp: job.UpdatedAt.Format(time.RFC3339Nano),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+
+		fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+
+		if snapshot != nil {
+			if writeEvent(w, sseSnapshotID, snapshot) != nil || job.Status.IsTerminal() {
+				return
+			}
+		}
+
+		for _, update := range history {
+			if writeEvent(w, update.EventID, []byte(update.Payload)) != nil || progress.IsTerminal(update.Status) {
+				return
+			}
+		}
+
+		if w.Flush() != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(sseHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case update, ok := <-sub.Updates():
+				if !ok {
+					return
+				}
+
+				if writeEvent(w, update.EventID, []byte(update.Payload)) != nil {
+					return
+				}
+
+				if progress.IsTerminal(update.Status) {
+					log.Debug("job finished, closing event stream")
+					return
+				}
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+
+				if w.Flush() != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+func writeEvent(w *bufio.Writer, id string, data []byte) error {
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+
+	fmt.Fprintf(w, "data: %s\n\n", data)
+
+	return w.Flush()
+}
+
+// GetEventHistory pages through the retained progress events of a job, oldest
+// first. Pass the last event_id of a page as after to get the next one.
+func (h *RealtimeHandler) GetEventHistory(c *fiber.Ctx) error {
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": c.Locals("user_id"),
+		"job_id":  c.Params("job_id"),
+	})
+
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionView, log)
+
+	if !ok {
+		return nil
+	}
+
+	after := c.Query("after")
+
+	if after != "" && !progress.ValidEventID(after) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid after event id",
+		})
+	}
+
+	limit := c.QueryInt("limit", 100)
+
+	if limit < 1 || limit > progress.StreamMaxLen {
+		limit = 100
+	}
+
+	history, err := progress.History(c.Context(), job.ID, after, int64(limit))
+
+	if err != nil {
+		log.Errorf("failed to read job event history: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to retrieve event history",
+		})
+	}
+
+	events := make([]progress.Message, 0, len(history))
+
+	for _, update := range history {
+		events = append(events, update.Message)
+	}
+
+	return c.JSON(fiber.Map{
+		"job_id":   job.ID,
+		"status":   job.Status,
+		"events":   events,
+		"has_more": len(events) == limit,
+	})
+}
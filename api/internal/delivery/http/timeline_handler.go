@@ -0,0 +1,99 @@
+package http
+
+import (
+	"time"
+	"transcribe/internal/authz"
+	"transcribe/internal/domain"
+	"transcribe/internal/progress"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type TimelineHandler struct {
+	jobEventRepo      *repository.JobEventRepository
+	transcriptionRepo *repository.TranscriptionRepository
+	authz             *authz.Service
+}
+
+func NewTimelineHandler() *TimelineHandler {
+	return &TimelineHandler{
+		jobEventRepo:      repository.NewJobEventRepository(),
+		transcriptionRepo: repository.NewTranscriptionRepository(),
+		authz:             authz.NewService(),
+	}
+}
+
+func (h *TimelineHandler) GetJobTimeline(c *fiber.Ctx) error {
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": c.Locals("user_id"),
+		"job_id":  c.Params("job_id"),
+	})
+
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionView, log)
+
+	if !ok {
+		return nil
+	}
+
+	events, err := h.jobEventRepo.FindByJobID(job.ID)
+
+	if err != nil {
+		log.Errorf("failed to fetch job timeline: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to retrieve timeline",
+		})
+	}
+
+	return c.JSON(buildTimeline(job, events, time.Now()))
+}
+
+// buildTimeline measures each event until the next one. The last event of a
+// job that is still running is measured until now; a final event has no
+// duration.
+func buildTimeline(job *domain.TranscriptionJob, events []domain.JobEvent, now time.Time) domain.TimelineResponse {
+	timeline := domain.TimelineResponse{
+		JobID:  job.ID,
+		Status: job.Status,
+		Events: make([]domain.JobEventResponse, 0, len(events)),
+		Stages: []domain.StageDuration{},
+	}
+
+	stages := make(map[string]int)
+
+	for i, event := range events {
+		resp := domain.JobEventResponse{JobEvent: event}
+
+		var end time.Time
+
+		if i+1 < len(events) {
+			end = events[i+1].CreatedAt
+		} else if !progress.IsTerminal(event.Stage) {
+			end = now
+		}
+
+		if !end.IsZero() {
+			seconds := end.Sub(event.CreatedAt).Seconds()
+			resp.Duration = &seconds
+
+			idx, ok := stages[event.Stage]
+
+			if !ok {
+				idx = len(timeline.Stages)
+				stages[event.Stage] = idx
+				timeline.Stages = append(timeline.Stages, domain.StageDuration{Stage: event.Stage})
+			}
+
+			timeline.Stages[idx].Duration += seconds
+			timeline.Stages[idx].Count++
+			timeline.Duration += seconds
+		}
+
+		timeline.Events = append(timeline.Events, resp)
+	}
+
+	return timeline
+}
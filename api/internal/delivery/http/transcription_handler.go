@@ -3,16 +3,24 @@ package http
 import (
 	"context"
 	"encoding/json"
-	"os"
+	"errors"
+	"fmt"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"transcribe/config"
+	"transcribe/internal/authz"
 	"transcribe/internal/domain"
+	"transcribe/internal/progress"
+	"transcribe/internal/queue"
 	"transcribe/internal/repository"
+	"transcribe/internal/search"
+	"transcribe/internal/transcript"
 	"transcribe/pkg/logger"
+	"transcribe/pkg/media"
+	"transcribe/pkg/subtitle"
 
 	"github.com/sirupsen/logrus"
 
@@ -22,11 +30,19 @@ import (
 
 type TranscriptionHandler struct {
 	transcriptionRepo *repository.TranscriptionRepository
+	segmentRepo       *repository.SegmentRepository
+	speakerRepo       *repository.SpeakerRepository
+	authz             *authz.Service
+	jobQueue          queue.Queue
 }
 
-func NewTranscriptionHandler() *TranscriptionHandler {
+func NewTranscriptionHandler(jobQueue queue.Queue) *TranscriptionHandler {
 	return &TranscriptionHandler{
 		transcriptionRepo: repository.NewTranscriptionRepository(),
+		segmentRepo:       repository.NewSegmentRepository(),
+		speakerRepo:       repository.NewSpeakerRepository(),
+		authz:             authz.NewService(),
+		jobQueue:          jobQueue,
 	}
 }
 
@@ -45,18 +61,9 @@ func (h *TranscriptionHandler) CreateJob(c *fiber.Ctx) error {
 		})
 	}
 
-	allowedTypes := []string{".mp3", ".wav", ".m4a", ".ogg", ".flac", ".mp4", ".avi", ".mov"}
 	ext := strings.ToLower(filepath.Ext(file.Filename))
-	isValid := false
 
-	for _, t := range allowedTypes {
-		if ext == t {
-			isValid = true
-			break
-		}
-	}
-
-	if !isValid {
+	if !isAllowedExtension(ext) {
 		log.Warnf("invalid file type: %s", ext)
 
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -74,24 +81,52 @@ func (h *TranscriptionHandler) CreateJob(c *fiber.Ctx) error {
 		})
 	}
 
-	jobID := uuid.New().String()
-	log = log.WithField("job_id", jobID)
+	options, err := parseTranscriptionOptions(func(key string) string {
+		return c.FormValue(key)
+	})
+
+	if err != nil {
+		log.Warnf("invalid transcription options: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	workspaceID, ok := resolveWorkspace(c, h.authz, c.FormValue("workspace_id"), log)
 
-	uploadDir := config.AppConfig.UploadDir
-	userDir := filepath.Join(uploadDir, "user_"+strconv.Itoa(int(userID)))
+	if !ok {
+		return nil
+	}
+
+	src, err := file.Open()
 
-	if err := os.MkdirAll(userDir, 0755); err != nil {
-		log.Errorf("failed to create upload directory: %v", err)
+	if err != nil {
+		log.Errorf("failed to read uploaded file: %v", err)
 
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to create upload directory",
+			"error": "failed to save file",
+		})
+	}
+
+	defer src.Close()
+
+	mediaInfo, err := media.Inspect(src, file.Size)
+
+	if err != nil {
+		log.Warnf("invalid media file: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": mediaError(err),
 		})
 	}
 
-	fileName := jobID + ext
-	filePath := filepath.Join(userDir, fileName)
+	jobID := uuid.New().String()
+	log = log.WithField("job_id", jobID)
 
-	if err := c.SaveFile(file, filePath); err != nil {
+	storageKey := uploadKey(userID, jobID, ext)
+
+	if err := config.Storage.Put(c.Context(), storageKey, src, file.Size, file.Header.Get("Content-Type")); err != nil {
 		log.Errorf("failed to save file: %v", err)
 
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -100,100 +135,429 @@ func (h *TranscriptionHandler) CreateJob(c *fiber.Ctx) error {
 	}
 
 	job := &domain.TranscriptionJob{
-		ID:       jobID,
-		UserID:   userID,
-		FileName: file.Filename,
-		FilePath: filePath,
-		FileSize: file.Size,
-		Status:   "queued",
+		ID:          jobID,
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		FileName:    file.Filename,
+		FilePath:    storageKey,
+		FileSize:    file.Size,
+		Duration:    mediaInfo.Duration,
+		Status:      domain.JobStatusQueued,
+		Options:     options,
+		MaxAttempts: config.AppConfig.QueueMaxAttempts,
 	}
 
-	if err := h.transcriptionRepo.Create(job); err != nil {
+	if err := h.transcriptionRepo.CreateWithOutbox(job, newEnqueueMessage(job)); err != nil {
 		log.Errorf("failed to create transcription job in db: %v", err)
 
-		os.Remove(filePath)
+		config.Storage.Delete(context.Background(), storageKey)
 
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "failed to create transcription job",
 		})
 	}
 
-	jobData := map[string]interface{}{
-		"job_id":    jobID,
-		"file_path": filePath,
-		"user_id":   userID,
+	recordCreated(job, "job created", log)
+
+	log.Info("transcription job created and queued successfully")
+
+	return c.Status(fiber.StatusCreated).JSON(domain.TranscriptionResponse{
+		JobID:       jobID,
+		WorkspaceID: workspaceID,
+		Status:      domain.JobStatusQueued,
+		Message:     "job created and queued for transcription",
+		Options:     &options,
+		Duration:    mediaInfo.Duration,
+	})
+}
+
+// maxCancelJobIDs caps the number of jobs in one bulk cancellation.
+const maxCancelJobIDs = 100
+
+var allowedExtensions = []string{".mp3", ".wav", ".m4a", ".ogg", ".flac", ".mp4", ".avi", ".mov"}
+
+func isAllowedExtension(ext string) bool {
+	for _, t := range allowedExtensions {
+		if ext == t {
+			return true
+		}
 	}
 
-	jobJSON, _ := json.Marshal(jobData)
+	return false
+}
 
-	ctx := context.Background()
+func findAuthorizedJob(c *fiber.Ctx, transcriptionRepo *repository.TranscriptionRepository, service *authz.Service, action authz.Action, log *logrus.Entry) (*domain.TranscriptionJob, bool) {
+	userID := c.Locals("user_id").(uint)
 
-	if err := config.RedisClient.RPush(ctx, "transcription_queue", jobJSON).Err(); err != nil {
-		log.Errorf("failed to queue job in redis: %v", err)
+	job, err := transcriptionRepo.FindByID(c.Params("job_id"))
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to queue job",
+	if err != nil {
+		log.Warnf("job not found: %v", err)
+
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "job not found",
 		})
+		return nil, false
 	}
 
-	log.Info("transcription job created and queued successfully")
+	if err := service.AuthorizeJob(userID, job, action); err != nil {
+		writeAuthzError(c, err, log)
+		return nil, false
+	}
 
-	return c.Status(fiber.StatusCreated).JSON(domain.TranscriptionResponse{
-		JobID:   jobID,
-		Status:  "queued",
-		Message: "job created and queued for transcription",
-	})
+	return job, true
 }
 
-func (h *TranscriptionHandler) GetJobStatus(c *fiber.Ctx) error {
+// jobScope limits listings to a single workspace when workspace_id is given,
+// otherwise to every job the caller can view.
+func (h *TranscriptionHandler) jobScope(c *fiber.Ctx, log *logrus.Entry) (domain.JobScope, bool) {
 	userID := c.Locals("user_id").(uint)
-	jobID := c.Params("job_id")
 
-	log := logger.Log.WithFields(logrus.Fields{
-		"user_id": userID,
-		"job_id":  jobID,
-	})
+	if raw := c.Query("workspace_id"); raw != "" {
+		workspaceID, err := strconv.ParseUint(raw, 10, 64)
+
+		if err != nil {
+			c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid workspace_id",
+			})
+			return domain.JobScope{}, false
+		}
+
+		if _, err := h.authz.AuthorizeWorkspace(userID, uint(workspaceID), authz.ActionView); err != nil {
+			writeAuthzError(c, err, log.WithField("workspace_id", workspaceID))
+			return domain.JobScope{}, false
+		}
 
-	job, err := h.transcriptionRepo.FindByID(jobID)
+		return domain.JobScope{WorkspaceIDs: []uint{uint(workspaceID)}}, true
+	}
+
+	scope, err := h.authz.JobScope(userID)
 
 	if err != nil {
-		log.Warnf("job not found: %v", err)
+		log.Errorf("failed to resolve job scope: %v", err)
 
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "job not found",
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to fetch jobs",
 		})
+		return domain.JobScope{}, false
 	}
 
-	if job.UserID != userID {
-		log.Warn("access denied to job")
+	return scope, true
+}
+
+func writeAuthzError(c *fiber.Ctx, err error, log *logrus.Entry) {
+	if errors.Is(err, authz.ErrForbidden) {
+		log.Warn("access denied")
 
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Access denied",
+		c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "access denied",
 		})
+		return
+	}
+
+	log.Errorf("failed to authorize request: %v", err)
+
+	c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "failed to authorize request",
+	})
+}
+
+// resolveWorkspace parses an optional workspace_id for a new job and checks
+// that the caller may add jobs to it.
+func resolveWorkspace(c *fiber.Ctx, service *authz.Service, raw string, log *logrus.Entry) (*uint, bool) {
+	if raw == "" {
+		return nil, true
+	}
+
+	id, err := strconv.ParseUint(raw, 10, 64)
+
+	if err != nil || id == 0 {
+		log.Warnf("invalid workspace id: %q", raw)
+
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid workspace_id",
+		})
+		return nil, false
+	}
+
+	workspaceID := uint(id)
+
+	if _, err := service.AuthorizeWorkspace(c.Locals("user_id").(uint), workspaceID, authz.ActionEdit); err != nil {
+		writeAuthzError(c, err, log.WithField("workspace_id", workspaceID))
+		return nil, false
+	}
+
+	return &workspaceID, true
+}
+
+// cancelJob moves the job to cancelling and signals the worker. A job still
+// waiting in the queue is taken out of it and cancelled right away; otherwise
+// it becomes cancelled once the worker acknowledges, or the reaper gives up
+// waiting for a worker that does not hold it. event names who cancelled it and
+// is recorded in the job's timeline. A job that reached a final status first
+// yields a *domain.TransitionError; a job already cancelling is left as it is.
+func cancelJob(ctx context.Context, transcriptionRepo *repository.TranscriptionRepository, jobQueue queue.Queue, job *domain.TranscriptionJob, event *domain.JobEvent, log *logrus.Entry) error {
+	if job.Status == domain.JobStatusCancelling {
+		return nil
+	}
+
+	if err := transcriptionRepo.Transition(job.ID, domain.JobStatusCancelling, nil); err != nil {
+		return err
+	}
+
+	waiting := job.Status == domain.JobStatusQueued || job.Status == domain.JobStatusRetrying
+	job.Status = domain.JobStatusCancelling
+
+	// A worker that misses the signal cannot overwrite the cancellation, it
+	// only finishes for nothing.
+	cancelKey := "job_cancellation:" + job.ID
+
+	if err := config.RedisClient.Set(ctx, cancelKey, "1", 24*time.Hour).Err(); err != nil {
+		log.Errorf("failed to set cancellation signal in redis: %v", err)
+	}
+
+	event.JobID = job.ID
+	event.Stage = string(domain.JobStatusCancelling)
+
+	if err := progress.Record(ctx, event); err != nil {
+		log.Warnf("failed to record cancellation: %v", err)
+	}
+
+	if !waiting {
+		log.Info("job cancellation signal sent")
+		return nil
+	}
+
+	withdrawn, err := queue.Withdraw(ctx, jobQueue, job.ID)
+
+	if err != nil {
+		log.Warnf("failed to remove cancelled job from the queue: %v", err)
+	}
+
+	if withdrawn && queue.CompleteCancel(ctx, transcriptionRepo, job.ID, "removed from the queue before a worker picked it up", log) {
+		job.Status = domain.JobStatusCancelled
+		return nil
+	}
+
+	log.Info("job cancellation signal sent")
+
+	return nil
+}
+
+// cancelOne cancels one job of a bulk request. job is nil when it does not
+// exist.
+func (h *TranscriptionHandler) cancelOne(ctx context.Context, userID uint, jobID string, job *domain.TranscriptionJob, log *logrus.Entry) domain.CancelJobResult {
+	result := domain.CancelJobResult{JobID: jobID}
+
+	if job == nil {
+		result.Error = "job not found"
+		return result
+	}
+
+	if err := h.authz.AuthorizeJob(userID, job, authz.ActionEdit); err != nil {
+		if !errors.Is(err, authz.ErrForbidden) {
+			log.Errorf("failed to authorize request: %v", err)
+		}
+
+		result.Error = "access denied"
+		return result
+	}
+
+	if job.Status.IsTerminal() {
+		result.Status = job.Status
+		result.Error = "job is already finished or cancelled"
+		return result
+	}
+
+	event := &domain.JobEvent{
+		Actor:   domain.JobEventActorUser,
+		ActorID: &userID,
+	}
+
+	err := cancelJob(ctx, h.transcriptionRepo, h.jobQueue, job, event, log)
+
+	var transitionErr *domain.TransitionError
+
+	switch {
+	case err == nil:
+		result.Status = job.Status
+	case errors.As(err, &transitionErr):
+		result.Status = transitionErr.From
+		result.Error = transitionErr.Error()
+	default:
+		log.Errorf("failed to cancel job: %v", err)
+		result.Error = "failed to cancel job"
+	}
+
+	return result
+}
+
+// writeTransitionError answers a status change the job's current status does
+// not allow with 409 and that status, and anything else with 500.
+func writeTransitionError(c *fiber.Ctx, err error, message string, log *logrus.Entry) error {
+	var transitionErr *domain.TransitionError
+
+	if errors.As(err, &transitionErr) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":  transitionErr.Error(),
+			"status": transitionErr.From,
+		})
+	}
+
+	log.Errorf("%s: %v", message, err)
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
+
+// recordCreated starts the timeline of a newly queued job.
+func recordCreated(job *domain.TranscriptionJob, message string, log *logrus.Entry) {
+	event := &domain.JobEvent{
+		JobID:   job.ID,
+		Stage:   string(domain.JobStatusQueued),
+		Message: message,
+		Actor:   domain.JobEventActorUser,
+		ActorID: &job.UserID,
+	}
+
+	if err := progress.Record(context.Background(), event); err != nil {
+		log.Warnf("failed to record queued event: %v", err)
+	}
+}
+
+func deleteJob(transcriptionRepo *repository.TranscriptionRepository, segmentRepo *repository.SegmentRepository, job *domain.TranscriptionJob, log *logrus.Entry) error {
+	if err := config.Storage.Delete(context.Background(), job.FilePath); err != nil {
+		log.Warnf("failed to delete file from filesystem: %v", err)
+	}
+
+	if err := transcriptionRepo.Delete(job.ID); err != nil {
+		return err
+	}
+
+	if err := segmentRepo.DeleteByJobID(job.ID); err != nil {
+		log.Warnf("failed to delete job from search index: %v", err)
+	}
+
+	if err := repository.NewJobEventRepository().DeleteByJobID(job.ID); err != nil {
+		log.Warnf("failed to delete job timeline: %v", err)
+	}
+
+	if err := config.RedisClient.Del(context.Background(), progress.Stream(job.ID)).Err(); err != nil {
+		log.Warnf("failed to delete job event history: %v", err)
+	}
+
+	log.Info("job deleted successfully")
+
+	return nil
+}
+
+func mediaError(err error) string {
+	if errors.Is(err, media.ErrUnsupported) {
+		return "unsupported media file. Allowed: mp3, wav, m4a, ogg, flac, mp4, avi, mov"
+	}
+
+	return "media file is corrupt or truncated"
+}
+
+func uploadKey(userID uint, jobID, ext string) string {
+	return fmt.Sprintf("user_%d/%s%s", userID, jobID, ext)
+}
+
+func newEnqueueMessage(job *domain.TranscriptionJob) *domain.OutboxMessage {
+	payload, _ := json.Marshal(&queue.Job{
+		JobID:       job.ID,
+		StorageKey:  job.FilePath,
+		UserID:      job.UserID,
+		Options:     job.Options,
+		MaxAttempts: job.MaxAttempts,
+	})
+
+	return &domain.OutboxMessage{
+		Topic:       domain.OutboxTopicEnqueueJob,
+		AggregateID: job.ID,
+		Payload:     string(payload),
+		Status:      domain.OutboxStatusPending,
+		AvailableAt: time.Now(),
+	}
+}
+
+func parseTranscriptionOptions(get func(key string) string) (domain.TranscriptionOptions, error) {
+	var options domain.TranscriptionOptions
+
+	if raw := get("options"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &options); err != nil {
+			return options, errors.New("invalid options: must be a JSON object")
+		}
+	} else {
+		options.Language = get("language")
+		options.Model = get("model")
+		options.Task = get("task")
+		options.Diarization = get("diarization")
+
+		if raw := get("num_speakers"); raw != "" {
+			numSpeakers, err := strconv.Atoi(raw)
+			if err != nil {
+				return options, errors.New("num_speakers must be a number")
+			}
+			options.NumSpeakers = numSpeakers
+		}
+	}
+
+	options.Normalize()
+
+	if err := options.Validate(); err != nil {
+		return options, err
+	}
+
+	return options, nil
+}
+
+func (h *TranscriptionHandler) GetJobStatus(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	jobID := c.Params("job_id")
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": userID,
+		"job_id":  jobID,
+	})
+
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionView, log)
+
+	if !ok {
+		return nil
 	}
 
 	response := domain.TranscriptionResponse{
 		JobID:       job.ID,
+		Revision:    job.Revision,
+		UserID:      job.UserID,
+		WorkspaceID: job.WorkspaceID,
 		Status:      job.Status,
 		FileName:    job.FileName,
 		FileSize:    job.FileSize,
 		Duration:    job.Duration,
+		Options:     &job.Options,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		NextRetryAt: job.NextRetryAt,
 		CreatedAt:   job.CreatedAt,
 		CompletedAt: job.CompletedAt,
 	}
 
-	if job.Status == "done" {
+	if job.Status == domain.JobStatusDone {
 		response.Text = job.Text
 
 		if job.Segments != "" {
 			var segments []domain.Segment
 			if err := json.Unmarshal([]byte(job.Segments), &segments); err == nil {
+				transcript.RenameSpeakers(segments, speakerNames(h.speakerRepo, job.ID, log))
 				response.Segments = segments
 			}
 		}
 	}
 
-	if job.Status == "failed" {
+	if job.Status == domain.JobStatusFailed || job.Status == domain.JobStatusRetrying {
 		response.ErrorMsg = job.ErrorMsg
 	}
 
@@ -218,7 +582,13 @@ func (h *TranscriptionHandler) GetUserJobs(c *fiber.Ctx) error {
 		pageSize = 10
 	}
 
-	jobs, total, err := h.transcriptionRepo.FindByUserID(userID, page, pageSize)
+	scope, ok := h.jobScope(c, log)
+
+	if !ok {
+		return nil
+	}
+
+	jobs, total, err := h.transcriptionRepo.FindByScope(scope, page, pageSize)
 
 	if err != nil {
 		log.Errorf("failed to fetch jobs for user: %v", err)
@@ -228,30 +598,49 @@ func (h *TranscriptionHandler) GetUserJobs(c *fiber.Ctx) error {
 		})
 	}
 
+	jobIDs := make([]string, len(jobs))
+	for i, job := range jobs {
+		jobIDs[i] = job.ID
+	}
+
+	names, err := h.speakerRepo.NamesForJobs(jobIDs)
+
+	if err != nil {
+		log.Warnf("failed to load speaker names: %v", err)
+	}
+
 	var responses []domain.TranscriptionResponse
 
 	for _, job := range jobs {
 		resp := domain.TranscriptionResponse{
 			JobID:       job.ID,
+			Revision:    job.Revision,
+			UserID:      job.UserID,
+			WorkspaceID: job.WorkspaceID,
 			Status:      job.Status,
 			FileName:    job.FileName,
 			FileSize:    job.FileSize,
 			Duration:    job.Duration,
+			Options:     &job.Options,
+			Attempts:    job.Attempts,
+			MaxAttempts: job.MaxAttempts,
+			NextRetryAt: job.NextRetryAt,
 			CreatedAt:   job.CreatedAt,
 			CompletedAt: job.CompletedAt,
 		}
-		if job.Status == "done" {
+		if job.Status == domain.JobStatusDone {
 			resp.Text = job.Text
 
 			if job.Segments != "" {
 				var segments []domain.Segment
 				if err := json.Unmarshal([]byte(job.Segments), &segments); err == nil {
+					transcript.RenameSpeakers(segments, names[job.ID])
 					resp.Segments = segments
 				}
 			}
 		}
 
-		if job.Status == "failed" {
+		if job.Status == domain.JobStatusFailed || job.Status == domain.JobStatusRetrying {
 			resp.ErrorMsg = job.ErrorMsg
 		}
 
@@ -280,93 +669,476 @@ func (h *TranscriptionHandler) DeleteJob(c *fiber.Ctx) error {
 		"job_id":  jobID,
 	})
 
-	job, err := h.transcriptionRepo.FindByID(jobID)
-	if err != nil {
-		log.Warnf("job not found for deletion: %v", err)
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionDelete, log)
 
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "job not found",
+	if !ok {
+		return nil
+	}
+
+	if err := deleteJob(h.transcriptionRepo, h.segmentRepo, job, log); err != nil {
+		log.Errorf("failed to delete job from db: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to delete job",
 		})
 	}
 
-	if job.UserID != userID {
-		log.Warn("access denied for job deletion")
+	return c.JSON(fiber.Map{
+		"message": "job deleted successfully",
+	})
+}
 
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "access denied",
+func (h *TranscriptionHandler) CancelJob(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	jobID := c.Params("job_id")
+	ctx := context.Background()
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": userID,
+		"job_id":  jobID,
+	})
+
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionEdit, log)
+
+	if !ok {
+		return nil
+	}
+
+	if job.Status.IsTerminal() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "job is already finished or cancelled",
 		})
 	}
 
-	if err := os.Remove(job.FilePath); err != nil {
-		log.Warnf("failed to delete file from filesystem: %v", err)
+	event := &domain.JobEvent{
+		Actor:   domain.JobEventActorUser,
+		ActorID: &userID,
 	}
 
-	if err := h.transcriptionRepo.Delete(jobID); err != nil {
-		log.Errorf("failed to delete job from db: %v", err)
+	if err := cancelJob(ctx, h.transcriptionRepo, h.jobQueue, job, event, log); err != nil {
+		return writeTransitionError(c, err, "failed to cancel job", log)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "job cancellation request sent",
+		"status":  job.Status,
+	})
+}
+
+// CancelJobs requests cancellation of several jobs. Each job is authorized and
+// cancelled on its own; the response reports the outcome per job.
+func (h *TranscriptionHandler) CancelJobs(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	req := new(domain.CancelJobsRequest)
+	ctx := context.Background()
+
+	log := logger.Log.WithField("user_id", userID)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warnf("invalid request body: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	jobIDs := make([]string, 0, len(req.JobIDs))
+	seen := make(map[string]bool, len(req.JobIDs))
+
+	for _, jobID := range req.JobIDs {
+		jobID = strings.TrimSpace(jobID)
+
+		if jobID != "" && !seen[jobID] {
+			seen[jobID] = true
+			jobIDs = append(jobIDs, jobID)
+		}
+	}
+
+	if len(jobIDs) == 0 || len(jobIDs) > maxCancelJobIDs {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("job_ids must contain between 1 and %d job ids", maxCancelJobIDs),
+		})
+	}
+
+	jobs, err := h.transcriptionRepo.FindByIDs(jobIDs)
+
+	if err != nil {
+		log.Errorf("failed to fetch jobs: %v", err)
 
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to delete job",
+			"error": "failed to cancel jobs",
 		})
 	}
 
-	log.Info("job deleted successfully")
+	byID := make(map[string]*domain.TranscriptionJob, len(jobs))
+
+	for i := range jobs {
+		byID[jobs[i].ID] = &jobs[i]
+	}
+
+	results := make([]domain.CancelJobResult, 0, len(jobIDs))
+	requested := 0
+
+	for _, jobID := range jobIDs {
+		result := h.cancelOne(ctx, userID, jobID, byID[jobID], log.WithField("job_id", jobID))
+
+		if result.Error == "" {
+			requested++
+		}
+
+		results = append(results, result)
+	}
+
+	log.Infof("cancellation requested for %d of %d jobs", requested, len(jobIDs))
 
 	return c.JSON(fiber.Map{
-		"message": "job deleted successfully",
+		"requested": requested,
+		"results":   results,
 	})
 }
 
-func (h *TranscriptionHandler) CancelJob(c *fiber.Ctx) error {
+func (h *TranscriptionHandler) ExportJob(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(uint)
 	jobID := c.Params("job_id")
-	ctx := context.Background()
 
 	log := logger.Log.WithFields(logrus.Fields{
 		"user_id": userID,
 		"job_id":  jobID,
 	})
 
-	job, err := h.transcriptionRepo.FindByID(jobID)
-	
+	format, err := subtitle.ParseFormat(c.Query("format", "srt"))
+
 	if err != nil {
-		log.Warnf("job not found for cancellation: %v", err)
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "job not found",
+		log.Warnf("invalid export format: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid format. Allowed: srt, vtt",
 		})
 	}
 
-	if job.UserID != userID {
-		log.Warn("access denied for job cancellation")
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "access denied",
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionView, log)
+
+	if !ok {
+		return nil
+	}
+
+	if job.Status != domain.JobStatusDone {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "job is not finished yet",
+		})
+	}
+
+	var segments []domain.Segment
+
+	if job.Segments != "" {
+		if err := json.Unmarshal([]byte(job.Segments), &segments); err != nil {
+			log.Errorf("failed to decode segments for export: %v", err)
+
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to read transcription segments",
+			})
+		}
+	}
+
+	transcript.RenameSpeakers(segments, speakerNames(h.speakerRepo, job.ID, log))
+
+	opts := subtitle.DefaultOptions()
+	opts.MaxCharsPerLine = c.QueryInt("max_chars", opts.MaxCharsPerLine)
+	opts.MaxLinesPerCue = c.QueryInt("max_lines", opts.MaxLinesPerCue)
+	opts.MaxCueDuration = c.QueryFloat("max_duration", opts.MaxCueDuration)
+	opts.IncludeSpeaker = c.QueryBool("speakers", opts.IncludeSpeaker)
+
+	body := subtitle.Render(format, segments, opts)
+
+	baseName := strings.TrimSuffix(job.FileName, filepath.Ext(job.FileName))
+	if baseName == "" {
+		baseName = job.ID
+	}
+
+	c.Attachment(baseName + "." + string(format))
+	c.Set(fiber.HeaderContentType, format.ContentType())
+
+	log.Infof("job exported as %s", format)
+
+	return c.SendString(body)
+}
+
+func (h *TranscriptionHandler) GetJobWords(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	jobID := c.Params("job_id")
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": userID,
+		"job_id":  jobID,
+	})
+
+	start := c.QueryFloat("start", 0)
+	end := c.QueryFloat("end", -1)
+
+	if start < 0 || (end >= 0 && end < start) {
+		log.Warnf("invalid word time range: start=%v end=%v", start, end)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid time range",
 		})
 	}
 
-	if job.Status == "done" || job.Status == "failed" || job.Status == "cancelled" {
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionView, log)
+
+	if !ok {
+		return nil
+	}
+
+	if job.Status != domain.JobStatusDone {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "job is already finished or cancelled",
+			"error": "job is not finished yet",
 		})
 	}
 
-	cancelKey := "job_cancellation:" + jobID
+	var segments []domain.Segment
+
+	if job.Segments != "" {
+		if err := json.Unmarshal([]byte(job.Segments), &segments); err != nil {
+			log.Errorf("failed to decode segments for words: %v", err)
+
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to read transcription segments",
+			})
+		}
+	}
+
+	transcript.RenameSpeakers(segments, speakerNames(h.speakerRepo, job.ID, log))
+
+	words := []domain.WordResponse{}
+
+	for _, seg := range segments {
+		if seg.EndTime < start || (end >= 0 && seg.StartTime > end) {
+			continue
+		}
+
+		for _, w := range seg.Words {
+			if w.End < start || (end >= 0 && w.Start > end) {
+				continue
+			}
+
+			words = append(words, domain.WordResponse{
+				Word:         w,
+				SegmentID:    seg.ID,
+				Speaker:      seg.Speaker,
+				SpeakerLabel: seg.SpeakerLabel,
+			})
+		}
+	}
+
+	log.Infof("retrieved %d words for job", len(words))
+
+	return c.JSON(fiber.Map{
+		"job_id": job.ID,
+		"words":  words,
+		"total":  len(words),
+	})
+}
+
+func (h *TranscriptionHandler) SearchJobs(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	log := logger.Log.WithField("user_id", userID)
+
+	query, err := search.ParseQuery(c.Query("q"))
+
+	if errors.Is(err, search.ErrNoSearchTerms) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "query only contains words that cannot be searched",
+			"ignored": query.Ignored,
+		})
+	}
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "query parameter q is required",
+		})
+	}
+
+	var speakers []string
+
+	for _, speaker := range strings.Split(c.Query("speaker"), ",") {
+		if speaker = strings.TrimSpace(speaker); speaker != "" {
+			speakers = append(speakers, speaker)
+		}
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 || pageSize > 50 {
+		pageSize = 10
+	}
+
+	scope, ok := h.jobScope(c, log)
+
+	if !ok {
+		return nil
+	}
+
+	booleanQuery := query.Boolean()
+
+	matches, total, err := h.segmentRepo.SearchJobs(scope, booleanQuery, speakers, page, pageSize)
+
+	if err != nil {
+		log.Errorf("failed to search transcripts: %v", err)
 
-	if err := config.RedisClient.Set(ctx, cancelKey, "1", 24*time.Hour).Err(); err != nil {
-		log.Errorf("failed to set cancellation signal in redis: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to process cancellation signal",
+			"error": "failed to search transcripts",
+		})
+	}
+
+	results := []domain.SearchResult{}
+
+	if len(matches) > 0 {
+		jobIDs := make([]string, len(matches))
+		for i, match := range matches {
+			jobIDs[i] = match.JobID
+		}
+
+		jobs, err := h.transcriptionRepo.FindByIDs(jobIDs)
+
+		if err != nil {
+			log.Errorf("failed to load matching jobs: %v", err)
+
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to search transcripts",
+			})
+		}
+
+		segments, err := h.segmentRepo.SearchSegments(scope, jobIDs, booleanQuery, speakers)
+
+		if err != nil {
+			log.Errorf("failed to load matching segments: %v", err)
+
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to search transcripts",
+			})
+		}
+
+		names, err := h.speakerRepo.NamesForJobs(jobIDs)
+
+		if err != nil {
+			log.Warnf("failed to load speaker names: %v", err)
+		}
+
+		jobsByID := make(map[string]domain.TranscriptionJob, len(jobs))
+		for _, job := range jobs {
+			jobsByID[job.ID] = job
+		}
+
+		hitsByJob := make(map[string][]domain.SearchHit)
+		for _, seg := range segments {
+			hit := domain.SearchHit{
+				SegmentID: seg.SegmentID,
+				StartTime: seg.StartTime,
+				EndTime:   seg.EndTime,
+				Speaker:   seg.Speaker,
+				Text:      seg.Text,
+				Snippet:   query.Highlight(seg.Text),
+			}
+
+			if name := names[seg.JobID][seg.Speaker]; name != "" {
+				hit.SpeakerLabel = hit.Speaker
+				hit.Speaker = name
+			}
+
+			hitsByJob[seg.JobID] = append(hitsByJob[seg.JobID], hit)
+		}
+
+		for _, match := range matches {
+			job, ok := jobsByID[match.JobID]
+			if !ok {
+				continue
+			}
+
+			results = append(results, domain.SearchResult{
+				JobID:     job.ID,
+				FileName:  job.FileName,
+				Status:    job.Status,
+				Score:     match.Score,
+				CreatedAt: job.CreatedAt,
+				Hits:      hitsByJob[job.ID],
+			})
+		}
+	}
+
+	log.Infof("search returned %d jobs", len(results))
+
+	response := fiber.Map{
+		"query":   c.Query("q"),
+		"results": results,
+		"pagination": fiber.Map{
+			"page":       page,
+			"page_size":  pageSize,
+			"total":      total,
+			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	}
+
+	if len(query.Ignored) > 0 {
+		response["ignored"] = query.Ignored
+	}
+
+	return c.JSON(response)
+}
+
+func (h *TranscriptionHandler) MoveJob(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	jobID := c.Params("job_id")
+	req := new(domain.MoveJobRequest)
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id": userID,
+		"job_id":  jobID,
+	})
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warnf("invalid request body: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
 		})
 	}
 
-	job.Status = "cancelled"
+	job, ok := findAuthorizedJob(c, h.transcriptionRepo, h.authz, authz.ActionManage, log)
 
-	if err := h.transcriptionRepo.UpdateStatus(job.ID, "cancelled", nil, nil, nil, nil); err != nil {
-		 log.Errorf("failed to update job status to cancelled: %v", err)
+	if !ok {
+		return nil
 	}
 
-	log.Info("job cancellation signal sent")
+	if req.WorkspaceID != nil {
+		if _, err := h.authz.AuthorizeWorkspace(userID, *req.WorkspaceID, authz.ActionEdit); err != nil {
+			writeAuthzError(c, err, log.WithField("workspace_id", *req.WorkspaceID))
+			return nil
+		}
+	}
+
+	if err := h.transcriptionRepo.UpdateWorkspace(job.ID, req.WorkspaceID); err != nil {
+		log.Errorf("failed to move job: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to move job",
+		})
+	}
+
+	if req.WorkspaceID != nil {
+		log.Infof("job moved to workspace %d", *req.WorkspaceID)
+	} else {
+		log.Info("job removed from workspace")
+	}
 
 	return c.JSON(fiber.Map{
-		"message": "job cancellation request sent",
-		"status":  "cancelled",
+		"message":      "job moved successfully",
+		"job_id":       job.ID,
+		"workspace_id": req.WorkspaceID,
 	})
 }
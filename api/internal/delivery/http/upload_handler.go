@@ -0,0 +1,502 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/authz"
+	"transcribe/internal/domain"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+	"transcribe/pkg/media"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,termination,expiration"
+	httpTimeFmt   = "Mon, 02 Jan 2006 15:04:05 GMT"
+)
+
+type UploadHandler struct {
+	uploadRepo *repository.UploadRepository
+	authz      *authz.Service
+}
+
+func NewUploadHandler() *UploadHandler {
+	return &UploadHandler{
+		uploadRepo: repository.NewUploadRepository(),
+		authz:      authz.NewService(),
+	}
+}
+
+func (h *UploadHandler) Options(c *fiber.Ctx) error {
+	c.Set("Tus-Resumable", tusVersion)
+	c.Set("Tus-Version", tusVersion)
+	c.Set("Tus-Extension", tusExtensions)
+	c.Set("Tus-Max-Size", strconv.FormatInt(config.AppConfig.UploadMaxSize, 10))
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *UploadHandler) TusResumable(c *fiber.Ctx) error {
+	c.Set("Tus-Resumable", tusVersion)
+
+	if c.Get("Tus-Resumable") != tusVersion {
+		c.Set("Tus-Version", tusVersion)
+
+		return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error": "unsupported tus version",
+		})
+	}
+
+	return c.Next()
+}
+
+func (h *UploadHandler) Create(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	log := logger.Log.WithField("user_id", userID)
+
+	length, err := strconv.ParseInt(c.Get("Upload-Length"), 10, 64)
+
+	if err != nil || length <= 0 {
+		log.Warn("missing or invalid upload length")
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Upload-Length header is required",
+		})
+	}
+
+	if length > config.AppConfig.UploadMaxSize {
+		log.Warnf("upload length exceeds limit: %d", length)
+
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"error": "upload exceeds maximum size",
+		})
+	}
+
+	metadata, err := parseUploadMetadata(c.Get("Upload-Metadata"))
+
+	if err != nil {
+		log.Warnf("invalid upload metadata: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid Upload-Metadata header",
+		})
+	}
+
+	fileName := metadata["filename"]
+	ext := strings.ToLower(filepath.Ext(fileName))
+
+	if fileName == "" || !isAllowedExtension(ext) {
+		log.Warnf("invalid upload file name: %q", fileName)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid file type. Allowed: mp3, wav, m4a, ogg, flac, mp4, avi, mov",
+		})
+	}
+
+	options, err := parseTranscriptionOptions(func(key string) string {
+		return metadata[key]
+	})
+
+	if err != nil {
+		log.Warnf("invalid transcription options: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	workspaceID, ok := resolveWorkspace(c, h.authz, metadata["workspace_id"], log)
+
+	if !ok {
+		return nil
+	}
+
+	optionsJSON, _ := json.Marshal(options)
+
+	upload := &domain.Upload{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		FileName:    fileName,
+		ContentType: metadata["filetype"],
+		Length:      length,
+		Options:     string(optionsJSON),
+		ExpiresAt:   time.Now().Add(config.AppConfig.UploadExpiration),
+	}
+
+	if err := h.uploadRepo.Create(upload); err != nil {
+		log.Errorf("failed to create upload: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to create upload",
+		})
+	}
+
+	log.WithField("upload_id", upload.ID).Infof("upload created (%d bytes)", length)
+
+	c.Set(fiber.HeaderLocation, c.BaseURL()+"/api/uploads/"+upload.ID)
+	c.Set("Upload-Expires", upload.ExpiresAt.UTC().Format(httpTimeFmt))
+
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+func (h *UploadHandler) Head(c *fiber.Ctx) error {
+	upload, status := h.findOwnedUpload(c)
+
+	if upload == nil {
+		return c.SendStatus(status)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	setUploadHeaders(c, upload)
+	c.Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *UploadHandler) Patch(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	uploadID := c.Params("upload_id")
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id":   userID,
+		"upload_id": uploadID,
+	})
+
+	if c.Get(fiber.HeaderContentType) != "application/offset+octet-stream" {
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error": "Content-Type must be application/offset+octet-stream",
+		})
+	}
+
+	upload, status := h.findOwnedUpload(c)
+
+	if upload == nil {
+		return c.SendStatus(status)
+	}
+
+	offset, err := strconv.ParseInt(c.Get("Upload-Offset"), 10, 64)
+
+	if err != nil || offset != upload.Offset || upload.CompletedAt != nil {
+		log.Warnf("upload offset mismatch: got %q, expected %d", c.Get("Upload-Offset"), upload.Offset)
+
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "upload offset mismatch",
+		})
+	}
+
+	body := c.Body()
+
+	if offset+int64(len(body)) > upload.Length {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"error": "chunk exceeds upload length",
+		})
+	}
+
+	if offset == 0 && len(body) >= media.SniffLength {
+		if _, err := media.Detect(body[:media.SniffLength]); err != nil {
+			log.Warnf("invalid media file: %v", err)
+
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": mediaError(err),
+			})
+		}
+	}
+
+	if len(body) > 0 {
+		// The key is unique per attempt: a request that loses a race for
+		// this offset must only clean up its own object.
+		chunk := &domain.UploadChunk{
+			UploadID:   upload.ID,
+			Offset:     offset,
+			Size:       int64(len(body)),
+			StorageKey: fmt.Sprintf("uploads/%s/%020d-%s", upload.ID, offset, uuid.NewString()),
+		}
+
+		if err := config.Storage.Put(c.Context(), chunk.StorageKey, bytes.NewReader(body), chunk.Size, "application/octet-stream"); err != nil {
+			log.Errorf("failed to store upload chunk: %v", err)
+
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to store upload chunk",
+			})
+		}
+
+		if err := h.uploadRepo.AppendChunk(upload, chunk); err != nil {
+			config.Storage.Delete(context.Background(), chunk.StorageKey)
+
+			if errors.Is(err, repository.ErrOffsetMismatch) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error": "upload offset mismatch",
+				})
+			}
+
+			log.Errorf("failed to record upload chunk: %v", err)
+
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to store upload chunk",
+			})
+		}
+
+		upload.Offset += chunk.Size
+	}
+
+	if upload.Offset == upload.Length {
+		job, err := h.complete(c.Context(), upload, log)
+
+		if isMediaError(err) {
+			log.Warnf("invalid media file: %v", err)
+
+			h.removeChunks(context.Background(), upload.ID)
+			h.uploadRepo.Delete(upload.ID)
+
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": mediaError(err),
+			})
+		}
+
+		if err != nil {
+			log.Errorf("failed to finalize upload: %v", err)
+
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to finalize upload",
+			})
+		}
+
+		upload.JobID = &job.ID
+	}
+
+	setUploadHeaders(c, upload)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *UploadHandler) Delete(c *fiber.Ctx) error {
+	upload, status := h.findOwnedUpload(c)
+
+	if upload == nil {
+		return c.SendStatus(status)
+	}
+
+	h.removeChunks(context.Background(), upload.ID)
+
+	if err := h.uploadRepo.Delete(upload.ID); err != nil {
+		logger.Log.WithField("upload_id", upload.ID).Errorf("failed to delete upload: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to delete upload",
+		})
+	}
+
+	logger.Log.WithField("upload_id", upload.ID).Info("upload terminated")
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *UploadHandler) findOwnedUpload(c *fiber.Ctx) (*domain.Upload, int) {
+	userID := c.Locals("user_id").(uint)
+
+	upload, err := h.uploadRepo.FindByID(c.Params("upload_id"))
+
+	if err != nil {
+		return nil, fiber.StatusNotFound
+	}
+
+	if upload.UserID != userID {
+		return nil, fiber.StatusForbidden
+	}
+
+	if upload.CompletedAt == nil && time.Now().After(upload.ExpiresAt) {
+		return nil, fiber.StatusGone
+	}
+
+	return upload, 0
+}
+
+func (h *UploadHandler) complete(ctx context.Context, upload *domain.Upload, log *logrus.Entry) (*domain.TranscriptionJob, error) {
+	chunks, err := h.uploadRepo.Chunks(upload.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var options domain.TranscriptionOptions
+	json.Unmarshal([]byte(upload.Options), &options)
+
+	jobID := uuid.New().String()
+	storageKey := uploadKey(upload.UserID, jobID, strings.ToLower(filepath.Ext(upload.FileName)))
+
+	reader := &chunkReader{ctx: ctx, chunks: chunks}
+	defer reader.Close()
+
+	if err := config.Storage.Put(ctx, storageKey, reader, upload.Length, upload.ContentType); err != nil {
+		return nil, err
+	}
+
+	mediaInfo, err := inspectStored(ctx, storageKey, upload.Length)
+
+	if err != nil {
+		config.Storage.Delete(context.Background(), storageKey)
+		return nil, err
+	}
+
+	job := &domain.TranscriptionJob{
+		ID:          jobID,
+		UserID:      upload.UserID,
+		WorkspaceID: upload.WorkspaceID,
+		FileName:    upload.FileName,
+		FilePath:    storageKey,
+		FileSize:    upload.Length,
+		Duration:    mediaInfo.Duration,
+		Status:      domain.JobStatusQueued,
+		Options:     options,
+		MaxAttempts: config.AppConfig.QueueMaxAttempts,
+	}
+
+	if err := h.uploadRepo.Complete(upload.ID, job, newEnqueueMessage(job)); err != nil {
+		config.Storage.Delete(context.Background(), storageKey)
+		return nil, err
+	}
+
+	for _, chunk := range chunks {
+		config.Storage.Delete(context.Background(), chunk.StorageKey)
+	}
+
+	recordCreated(job, "upload completed", log.WithField("job_id", jobID))
+
+	log.WithField("job_id", jobID).Info("upload completed, transcription job queued")
+
+	return job, nil
+}
+
+func (h *UploadHandler) removeChunks(ctx context.Context, uploadID string) {
+	chunks, err := h.uploadRepo.Chunks(uploadID)
+
+	if err != nil {
+		return
+	}
+
+	for _, chunk := range chunks {
+		config.Storage.Delete(ctx, chunk.StorageKey)
+	}
+}
+
+func inspectStored(ctx context.Context, key string, size int64) (*media.Info, error) {
+	rc, err := config.Storage.Open(ctx, key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rc.Close()
+
+	ra, ok := rc.(io.ReaderAt)
+
+	if !ok {
+		return nil, errors.New("storage object does not support random access")
+	}
+
+	return media.Inspect(ra, size)
+}
+
+func isMediaError(err error) bool {
+	return errors.Is(err, media.ErrUnsupported) || errors.Is(err, media.ErrCorrupt)
+}
+
+func setUploadHeaders(c *fiber.Ctx, upload *domain.Upload) {
+	c.Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+
+	if upload.CompletedAt == nil && upload.JobID == nil {
+		c.Set("Upload-Expires", upload.ExpiresAt.UTC().Format(httpTimeFmt))
+	}
+
+	if upload.JobID != nil {
+		c.Set("Upload-Job-Id", *upload.JobID)
+	}
+}
+
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+
+		if pair == "" {
+			continue
+		}
+
+		key, encoded, _ := strings.Cut(pair, " ")
+
+		value, err := base64.StdEncoding.DecodeString(encoded)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s", key)
+		}
+
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
+
+type chunkReader struct {
+	ctx     context.Context
+	chunks  []domain.UploadChunk
+	current io.ReadCloser
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.chunks) == 0 {
+				return 0, io.EOF
+			}
+
+			rc, err := config.Storage.Open(r.ctx, r.chunks[0].StorageKey)
+
+			if err != nil {
+				return 0, err
+			}
+
+			r.current = rc
+			r.chunks = r.chunks[1:]
+		}
+
+		n, err := r.current.Read(p)
+
+		if errors.Is(err, io.EOF) {
+			r.current.Close()
+			r.current = nil
+
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+
+		return n, err
+	}
+}
+
+func (r *chunkReader) Close() error {
+	if r.current != nil {
+		return r.current.Close()
+	}
+
+	return nil
+}
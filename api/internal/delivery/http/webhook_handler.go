@@ -0,0 +1,436 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"transcribe/internal/domain"
+	"transcribe/internal/repository"
+	"transcribe/internal/webhook"
+	"transcribe/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type WebhookHandler struct {
+	webhookRepo *repository.WebhookRepository
+}
+
+func NewWebhookHandler() *WebhookHandler {
+	return &WebhookHandler{
+		webhookRepo: repository.NewWebhookRepository(),
+	}
+}
+
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	req := new(domain.CreateWebhookRequest)
+
+	log := logger.Log.WithField("user_id", userID)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warnf("invalid request body: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := validateWebhookURL(req.URL); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	events, err := normalizeWebhookEvents(req.Events)
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	secret, err := webhook.NewSecret()
+
+	if err != nil {
+		log.Errorf("failed to generate webhook secret: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to create webhook",
+		})
+	}
+
+	hook := &domain.Webhook{
+		UserID:      userID,
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      secret,
+		Events:      strings.Join(events, ","),
+		Active:      true,
+	}
+
+	if err := h.webhookRepo.Create(hook); err != nil {
+		log.Errorf("failed to create webhook: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to create webhook",
+		})
+	}
+
+	log.WithField("webhook_id", hook.ID).Info("webhook created")
+
+	resp := webhookResponse(hook)
+	resp.Secret = secret
+
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+func (h *WebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	webhooks, err := h.webhookRepo.FindByUserID(userID)
+
+	if err != nil {
+		logger.Log.WithField("user_id", userID).Errorf("failed to list webhooks: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to retrieve webhooks",
+		})
+	}
+
+	responses := make([]domain.WebhookResponse, 0, len(webhooks))
+
+	for i := range webhooks {
+		responses = append(responses, webhookResponse(&webhooks[i]))
+	}
+
+	return c.JSON(fiber.Map{
+		"webhooks": responses,
+	})
+}
+
+func (h *WebhookHandler) GetWebhook(c *fiber.Ctx) error {
+	hook, ok := h.findOwnedWebhook(c)
+
+	if !ok {
+		return nil
+	}
+
+	return c.JSON(webhookResponse(hook))
+}
+
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	hook, ok := h.findOwnedWebhook(c)
+
+	if !ok {
+		return nil
+	}
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id":    hook.UserID,
+		"webhook_id": hook.ID,
+	})
+
+	req := new(domain.UpdateWebhookRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warnf("invalid request body: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	updates := map[string]interface{}{}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		updates["url"] = *req.URL
+	}
+
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+
+	if req.Events != nil {
+		events, err := normalizeWebhookEvents(req.Events)
+
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		updates["events"] = strings.Join(events, ",")
+	}
+
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+
+	if len(updates) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "no fields to update",
+		})
+	}
+
+	if err := h.webhookRepo.Update(hook.ID, updates); err != nil {
+		log.Errorf("failed to update webhook: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to update webhook",
+		})
+	}
+
+	hook, _ = h.webhookRepo.FindByID(hook.ID)
+
+	log.Info("webhook updated")
+
+	return c.JSON(webhookResponse(hook))
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	hook, ok := h.findOwnedWebhook(c)
+
+	if !ok {
+		return nil
+	}
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id":    hook.UserID,
+		"webhook_id": hook.ID,
+	})
+
+	if err := h.webhookRepo.Delete(hook.ID); err != nil {
+		log.Errorf("failed to delete webhook: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to delete webhook",
+		})
+	}
+
+	log.Info("webhook deleted")
+
+	return c.JSON(fiber.Map{
+		"message": "webhook deleted successfully",
+	})
+}
+
+func (h *WebhookHandler) RotateSecret(c *fiber.Ctx) error {
+	hook, ok := h.findOwnedWebhook(c)
+
+	if !ok {
+		return nil
+	}
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id":    hook.UserID,
+		"webhook_id": hook.ID,
+	})
+
+	secret, err := webhook.NewSecret()
+
+	if err == nil {
+		err = h.webhookRepo.Update(hook.ID, map[string]interface{}{"secret": secret})
+	}
+
+	if err != nil {
+		log.Errorf("failed to rotate webhook secret: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to rotate webhook secret",
+		})
+	}
+
+	log.Info("webhook secret rotated")
+
+	resp := webhookResponse(hook)
+	resp.Secret = secret
+
+	return c.JSON(resp)
+}
+
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	hook, ok := h.findOwnedWebhook(c)
+
+	if !ok {
+		return nil
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	deliveries, total, err := h.webhookRepo.FindDeliveries(hook.ID, page, pageSize)
+
+	if err != nil {
+		logger.Log.WithField("webhook_id", hook.ID).Errorf("failed to list webhook deliveries: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to retrieve deliveries",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"deliveries": deliveries,
+		"pagination": fiber.Map{
+			"page":       page,
+			"page_size":  pageSize,
+			"total":      total,
+			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+func (h *WebhookHandler) Redeliver(c *fiber.Ctx) error {
+	hook, ok := h.findOwnedWebhook(c)
+
+	if !ok {
+		return nil
+	}
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id":    hook.UserID,
+		"webhook_id": hook.ID,
+	})
+
+	deliveryID, err := strconv.ParseUint(c.Params("delivery_id"), 10, 64)
+
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "delivery not found",
+		})
+	}
+
+	original, err := h.webhookRepo.FindDelivery(hook.ID, uint(deliveryID))
+
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "delivery not found",
+		})
+	}
+
+	delivery := &domain.WebhookDelivery{
+		WebhookID:     hook.ID,
+		JobID:         original.JobID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        domain.WebhookDeliveryPending,
+		NextAttemptAt: time.Now(),
+		RedeliveryOf:  &original.ID,
+	}
+
+	if _, err := h.webhookRepo.CreateDelivery(delivery); err != nil {
+		log.Errorf("failed to schedule redelivery: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to schedule redelivery",
+		})
+	}
+
+	log.WithField("delivery_id", delivery.ID).Infof("redelivery of %d scheduled", original.ID)
+
+	return c.Status(fiber.StatusAccepted).JSON(delivery)
+}
+
+func (h *WebhookHandler) findOwnedWebhook(c *fiber.Ctx) (*domain.Webhook, bool) {
+	userID := c.Locals("user_id").(uint)
+
+	webhookID, err := strconv.ParseUint(c.Params("webhook_id"), 10, 64)
+
+	if err != nil {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "webhook not found",
+		})
+
+		return nil, false
+	}
+
+	hook, err := h.webhookRepo.FindByID(uint(webhookID))
+
+	if err != nil {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "webhook not found",
+		})
+
+		return nil, false
+	}
+
+	if hook.UserID != userID {
+		logger.Log.WithFields(logrus.Fields{
+			"user_id":    userID,
+			"webhook_id": hook.ID,
+		}).Warn("access denied for webhook")
+
+		c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "access denied",
+		})
+
+		return nil, false
+	}
+
+	return hook, true
+}
+
+func webhookResponse(hook *domain.Webhook) domain.WebhookResponse {
+	return domain.WebhookResponse{
+		Webhook: *hook,
+		Events:  strings.Split(hook.Events, ","),
+	}
+}
+
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := webhook.CheckURL(ctx, u); err != nil {
+		return errors.New("url must point to a public address")
+	}
+
+	return nil
+}
+
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	if len(events) == 0 {
+		return domain.WebhookEvents, nil
+	}
+
+	var normalized []string
+
+	for _, event := range events {
+		event = strings.ToLower(strings.TrimSpace(event))
+
+		if !slices.Contains(domain.WebhookEvents, event) {
+			return nil, errors.New("invalid event. Allowed: " + strings.Join(domain.WebhookEvents, ", "))
+		}
+
+		if !slices.Contains(normalized, event) {
+			normalized = append(normalized, event)
+		}
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,514 @@
+package http
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"transcribe/internal/authz"
+	"transcribe/internal/domain"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type WorkspaceHandler struct {
+	workspaceRepo *repository.WorkspaceRepository
+	userRepo      *repository.UserRepository
+	authz         *authz.Service
+}
+
+func NewWorkspaceHandler() *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceRepo: repository.NewWorkspaceRepository(),
+		userRepo:      repository.NewUserRepository(),
+		authz:         authz.NewService(),
+	}
+}
+
+func (h *WorkspaceHandler) CreateWorkspace(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	req := new(domain.WorkspaceRequest)
+
+	log := logger.Log.WithField("user_id", userID)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warnf("invalid request body: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	name, err := normalizeWorkspaceName(req.Name)
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	workspace := &domain.Workspace{
+		Name:      name,
+		CreatedBy: userID,
+	}
+
+	if err := h.workspaceRepo.Create(workspace); err != nil {
+		log.Errorf("failed to create workspace: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to create workspace",
+		})
+	}
+
+	log.WithField("workspace_id", workspace.ID).Info("workspace created")
+
+	return c.Status(fiber.StatusCreated).JSON(domain.WorkspaceResponse{
+		Workspace: *workspace,
+		Role:      domain.WorkspaceRoleOwner,
+	})
+}
+
+func (h *WorkspaceHandler) ListWorkspaces(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	memberships, err := h.workspaceRepo.FindMemberships(userID)
+
+	if err != nil {
+		logger.Log.WithField("user_id", userID).Errorf("failed to fetch workspaces: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to fetch workspaces",
+		})
+	}
+
+	workspaces := make([]domain.WorkspaceResponse, 0, len(memberships))
+
+	for _, member := range memberships {
+		workspaces = append(workspaces, domain.WorkspaceResponse{
+			Workspace: member.Workspace,
+			Role:      member.Role,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"workspaces": workspaces,
+	})
+}
+
+func (h *WorkspaceHandler) GetWorkspace(c *fiber.Ctx) error {
+	workspace, member, ok := h.findWorkspace(c, authz.ActionView)
+
+	if !ok {
+		return nil
+	}
+
+	members, ok := h.members(c, workspace.ID)
+
+	if !ok {
+		return nil
+	}
+
+	return c.JSON(domain.WorkspaceResponse{
+		Workspace: *workspace,
+		Role:      member.Role,
+		Members:   members,
+	})
+}
+
+func (h *WorkspaceHandler) UpdateWorkspace(c *fiber.Ctx) error {
+	workspace, member, ok := h.findWorkspace(c, authz.ActionManage)
+
+	if !ok {
+		return nil
+	}
+
+	req := new(domain.WorkspaceRequest)
+
+	log := h.logger(c, workspace.ID)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warnf("invalid request body: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	name, err := normalizeWorkspaceName(req.Name)
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.workspaceRepo.Update(workspace.ID, map[string]interface{}{"name": name}); err != nil {
+		log.Errorf("failed to update workspace: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to update workspace",
+		})
+	}
+
+	workspace.Name = name
+
+	log.Info("workspace updated")
+
+	return c.JSON(domain.WorkspaceResponse{
+		Workspace: *workspace,
+		Role:      member.Role,
+	})
+}
+
+func (h *WorkspaceHandler) DeleteWorkspace(c *fiber.Ctx) error {
+	workspace, _, ok := h.findWorkspace(c, authz.ActionDelete)
+
+	if !ok {
+		return nil
+	}
+
+	log := h.logger(c, workspace.ID)
+
+	if err := h.workspaceRepo.Delete(workspace.ID); err != nil {
+		log.Errorf("failed to delete workspace: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to delete workspace",
+		})
+	}
+
+	log.Info("workspace deleted")
+
+	return c.JSON(fiber.Map{
+		"message": "workspace deleted successfully",
+	})
+}
+
+func (h *WorkspaceHandler) ListMembers(c *fiber.Ctx) error {
+	workspace, _, ok := h.findWorkspace(c, authz.ActionView)
+
+	if !ok {
+		return nil
+	}
+
+	members, ok := h.members(c, workspace.ID)
+
+	if !ok {
+		return nil
+	}
+
+	return c.JSON(fiber.Map{
+		"members": members,
+	})
+}
+
+func (h *WorkspaceHandler) AddMember(c *fiber.Ctx) error {
+	workspace, _, ok := h.findWorkspace(c, authz.ActionManage)
+
+	if !ok {
+		return nil
+	}
+
+	req := new(domain.AddMemberRequest)
+
+	log := h.logger(c, workspace.ID)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warnf("invalid request body: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	if !slices.Contains(domain.WorkspaceRoles, req.Role) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid role. Allowed: " + strings.Join(domain.WorkspaceRoles, ", "),
+		})
+	}
+
+	user, err := h.userRepo.FindByEmail(strings.TrimSpace(req.Email))
+
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "user not found",
+		})
+	}
+
+	member := &domain.WorkspaceMember{
+		WorkspaceID: workspace.ID,
+		UserID:      user.ID,
+		Role:        req.Role,
+	}
+
+	if err := h.workspaceRepo.AddMember(member); err != nil {
+		if errors.Is(err, repository.ErrMemberExists) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Errorf("failed to add workspace member: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to add member",
+		})
+	}
+
+	member.User = *user
+
+	log.WithField("member_id", user.ID).Infof("member added as %s", req.Role)
+
+	return c.Status(fiber.StatusCreated).JSON(memberResponse(member))
+}
+
+func (h *WorkspaceHandler) UpdateMember(c *fiber.Ctx) error {
+	workspace, _, ok := h.findWorkspace(c, authz.ActionManage)
+
+	if !ok {
+		return nil
+	}
+
+	target, ok := h.findMember(c, workspace.ID)
+
+	if !ok {
+		return nil
+	}
+
+	req := new(domain.UpdateMemberRequest)
+
+	log := h.logger(c, workspace.ID).WithField("member_id", target.UserID)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warnf("invalid request body: %v", err)
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid request body",
+		})
+	}
+
+	if !slices.Contains(domain.WorkspaceRoles, req.Role) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid role. Allowed: " + strings.Join(domain.WorkspaceRoles, ", "),
+		})
+	}
+
+	if target.Role == domain.WorkspaceRoleOwner && req.Role != domain.WorkspaceRoleOwner {
+		if !h.hasAnotherOwner(c, workspace.ID, log) {
+			return nil
+		}
+	}
+
+	if err := h.workspaceRepo.UpdateMemberRole(workspace.ID, target.UserID, req.Role); err != nil {
+		log.Errorf("failed to update member role: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to update member",
+		})
+	}
+
+	target.Role = req.Role
+
+	log.Infof("member role changed to %s", req.Role)
+
+	return c.JSON(memberResponse(target))
+}
+
+// RemoveMember lets owners remove anyone and any member remove themselves.
+func (h *WorkspaceHandler) RemoveMember(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	action := authz.ActionManage
+
+	if c.Params("user_id") == strconv.FormatUint(uint64(userID), 10) {
+		action = authz.ActionView
+	}
+
+	workspace, _, ok := h.findWorkspace(c, action)
+
+	if !ok {
+		return nil
+	}
+
+	target, ok := h.findMember(c, workspace.ID)
+
+	if !ok {
+		return nil
+	}
+
+	log := h.logger(c, workspace.ID).WithField("member_id", target.UserID)
+
+	if target.Role == domain.WorkspaceRoleOwner && !h.hasAnotherOwner(c, workspace.ID, log) {
+		return nil
+	}
+
+	if err := h.workspaceRepo.RemoveMember(workspace.ID, target.UserID); err != nil {
+		log.Errorf("failed to remove workspace member: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to remove member",
+		})
+	}
+
+	log.Info("member removed from workspace")
+
+	return c.JSON(fiber.Map{
+		"message": "member removed successfully",
+	})
+}
+
+// findWorkspace reports non-members as not found so workspace ids are not
+// disclosed, and members lacking the action as forbidden.
+func (h *WorkspaceHandler) findWorkspace(c *fiber.Ctx, action authz.Action) (*domain.Workspace, *domain.WorkspaceMember, bool) {
+	userID := c.Locals("user_id").(uint)
+
+	workspaceID, err := strconv.ParseUint(c.Params("workspace_id"), 10, 64)
+
+	if err != nil {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "workspace not found",
+		})
+
+		return nil, nil, false
+	}
+
+	log := h.logger(c, uint(workspaceID))
+
+	member, err := h.authz.AuthorizeWorkspace(userID, uint(workspaceID), action)
+
+	if errors.Is(err, authz.ErrForbidden) && member == nil {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "workspace not found",
+		})
+
+		return nil, nil, false
+	}
+
+	if err != nil {
+		writeAuthzError(c, err, log)
+
+		return nil, nil, false
+	}
+
+	workspace, err := h.workspaceRepo.FindByID(uint(workspaceID))
+
+	if err != nil {
+		if errors.Is(err, repository.ErrWorkspaceNotFound) {
+			c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "workspace not found",
+			})
+		} else {
+			log.Errorf("failed to fetch workspace: %v", err)
+
+			c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to fetch workspace",
+			})
+		}
+
+		return nil, nil, false
+	}
+
+	return workspace, member, true
+}
+
+func (h *WorkspaceHandler) findMember(c *fiber.Ctx, workspaceID uint) (*domain.WorkspaceMember, bool) {
+	userID, err := strconv.ParseUint(c.Params("user_id"), 10, 64)
+
+	if err == nil {
+		member, err := h.workspaceRepo.FindMember(workspaceID, uint(userID))
+
+		if err == nil {
+			if user, err := h.userRepo.FindByID(member.UserID); err == nil {
+				member.User = *user
+			}
+
+			return member, true
+		}
+	}
+
+	c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		"error": "member not found",
+	})
+
+	return nil, false
+}
+
+func (h *WorkspaceHandler) members(c *fiber.Ctx, workspaceID uint) ([]domain.MemberResponse, bool) {
+	members, err := h.workspaceRepo.FindMembers(workspaceID)
+
+	if err != nil {
+		h.logger(c, workspaceID).Errorf("failed to fetch workspace members: %v", err)
+
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to fetch members",
+		})
+
+		return nil, false
+	}
+
+	responses := make([]domain.MemberResponse, 0, len(members))
+
+	for i := range members {
+		responses = append(responses, memberResponse(&members[i]))
+	}
+
+	return responses, true
+}
+
+func (h *WorkspaceHandler) hasAnotherOwner(c *fiber.Ctx, workspaceID uint, log *logrus.Entry) bool {
+	owners, err := h.workspaceRepo.CountOwners(workspaceID)
+
+	if err != nil {
+		log.Errorf("failed to count workspace owners: %v", err)
+
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to update member",
+		})
+
+		return false
+	}
+
+	if owners <= 1 {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "workspace must keep at least one owner",
+		})
+
+		return false
+	}
+
+	return true
+}
+
+func (h *WorkspaceHandler) logger(c *fiber.Ctx, workspaceID uint) *logrus.Entry {
+	return logger.Log.WithFields(logrus.Fields{
+		"user_id":      c.Locals("user_id"),
+		"workspace_id": workspaceID,
+	})
+}
+
+func memberResponse(member *domain.WorkspaceMember) domain.MemberResponse {
+	return domain.MemberResponse{
+		UserID:   member.UserID,
+		Name:     member.User.Name,
+		Email:    member.User.Email,
+		Role:     member.Role,
+		JoinedAt: member.CreatedAt,
+	}
+}
+
+func normalizeWorkspaceName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return "", errors.New("name is required")
+	}
+
+	if len(name) > 100 {
+		return "", errors.New("name must be at most 100 characters")
+	}
+
+	return name, nil
+}
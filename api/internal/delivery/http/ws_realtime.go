@@ -2,21 +2,36 @@ package http
 
 import (
 	"context"
-	"fmt"
-	"transcribe/config"
+	"encoding/json"
+	"sync"
+	"time"
+	"transcribe/internal/authz"
+	"transcribe/internal/domain"
+	"transcribe/internal/progress"
 	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
 
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 )
 
+const (
+	maxStreamJobIDs = 100
+
+	wsWriteWait    = 10 * time.Second
+	wsPongWait     = 60 * time.Second
+	wsPingInterval = wsPongWait * 9 / 10
+)
+
 type RealtimeHandler struct {
 	transcriptionRepo *repository.TranscriptionRepository
+	authz             *authz.Service
 }
 
 func NewRealtimeHandler() *RealtimeHandler {
 	return &RealtimeHandler{
 		transcriptionRepo: repository.NewTranscriptionRepository(),
+		authz:             authz.NewService(),
 	}
 }
 
@@ -28,58 +43,334 @@ func (h *RealtimeHandler) WSUpgrade(c *fiber.Ctx) error {
 	return fiber.ErrUpgradeRequired
 }
 
+// ListenForProgress replays the job's retained events after last_event_id,
+// or all of them when it is omitted, and then streams new ones. Nothing is
+// replayed or streamed until the job has been loaded and the caller may view
+// it.
 func (h *RealtimeHandler) ListenForProgress(c *websocket.Conn) {
 	jobID := c.Params("job_id")
-	ctx := context.Background()
-	
+	lastEventID := c.Query("last_event_id")
+	log := logger.Log.WithField("job_id", jobID)
+
+	if lastEventID != "" && !progress.ValidEventID(lastEventID) {
+		c.WriteJSON(fiber.Map{
+			"status": "error",
+			"error":  "invalid last event id",
+		})
+		c.Close()
+		return
+	}
+
 	job, err := h.transcriptionRepo.FindByID(jobID)
-	if err == nil {
-		userID := c.Locals("user_id").(uint)
-		if job.UserID != userID {
-			c.WriteJSON(fiber.Map{
-				"status": "error", 
-				"error": "forbidden: you do not have access to this job",
-			})
-			c.Close()
+	if err != nil {
+		log.Warnf("job not found: %v", err)
+		c.WriteJSON(fiber.Map{
+			"status": "error",
+			"error":  "job not found",
+		})
+		c.Close()
+		return
+	}
+
+	userID := c.Locals("user_id").(uint)
+	if err := h.authz.AuthorizeJob(userID, job, authz.ActionView); err != nil {
+		c.WriteJSON(fiber.Map{
+			"status": "error",
+			"error":  "forbidden: you do not have access to this job",
+		})
+		c.Close()
+		return
+	}
+
+	sub, history, err := progress.Follow(context.Background(), job.ID, lastEventID)
+	if err != nil {
+		log.Errorf("failed to follow job progress: %v", err)
+		c.WriteJSON(fiber.Map{
+			"status": "error",
+			"error":  "failed to subscribe to job progress",
+		})
+		c.Close()
+		return
+	}
+
+	session := newWSSession(c)
+
+	initialMsg := fiber.Map{
+		"job_id":  job.ID,
+		"status":  job.Status,
+		"message": "connected. current status fetched.",
+	}
+
+	if job.Status.IsTerminal() {
+		initialMsg["final"] = true
+	}
+
+	if err := session.writeJSON(initialMsg); err != nil {
+		sub.Close()
+		return
+	}
+
+	for _, update := range history {
+		if err := session.writeText(update.Payload); err != nil {
+			sub.Close()
 			return
 		}
+	}
+
+	session.run(sub, nil, func([]byte) error { return nil })
+}
+
+// ListenForUserProgress streams progress for every job the user can view over
+// one connection. Clients narrow the stream with subscribe and unsubscribe
+// commands; see jobFilter.
+func (h *RealtimeHandler) ListenForUserProgress(c *websocket.Conn) {
+	userID := c.Locals("user_id").(uint)
+	log := logger.Log.WithField("user_id", userID)
+
+	sub, err := progress.SubscribeUser(context.Background(), userID)
+	if err != nil {
+		log.Errorf("failed to subscribe to user progress: %v", err)
+		c.WriteJSON(fiber.Map{
+			"status": "error",
+			"error":  "failed to subscribe to job progress",
+		})
+		c.Close()
+		return
+	}
+
+	session := newWSSession(c)
+
+	if err := session.writeJSON(fiber.Map{
+		"status":  "connected",
+		"message": "connected. streaming updates for all of your jobs.",
+	}); err != nil {
+		sub.Close()
+		return
+	}
 
-		initialMsg := fiber.Map{
-			"job_id":  job.ID,
-			"status":  job.Status,
-			"message": "connected. current status fetched.",
+	filter := newJobFilter()
+
+	session.run(sub, filter.allows, func(data []byte) error {
+		return h.handleStreamCommand(session, filter, userID, data)
+	})
+}
+
+func (h *RealtimeHandler) handleStreamCommand(session *wsSession, filter *jobFilter, userID uint, data []byte) error {
+	var cmd domain.JobStreamCommand
+
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return session.writeJSON(fiber.Map{"status": "error", "error": "invalid message"})
+	}
+
+	if len(cmd.JobIDs) > maxStreamJobIDs {
+		return session.writeJSON(fiber.Map{"status": "error", "error": "too many job_ids, the maximum is 100"})
+	}
+
+	switch cmd.Action {
+	case "subscribe":
+		filter.subscribe(cmd.JobIDs)
+
+		if err := session.writeJSON(fiber.Map{"action": "subscribed", "job_ids": cmd.JobIDs}); err != nil {
+			return err
 		}
-		
-		if job.Status == "done" || job.Status == "failed" {
-			initialMsg["final"] = true
+
+		return h.sendSnapshots(session, userID, cmd.JobIDs)
+	case "unsubscribe":
+		filter.unsubscribe(cmd.JobIDs)
+
+		return session.writeJSON(fiber.Map{"action": "unsubscribed", "job_ids": cmd.JobIDs})
+	default:
+		return session.writeJSON(fiber.Map{"status": "error", "error": "unknown action"})
+	}
+}
+
+// sendSnapshots sends the current status of newly subscribed jobs, so a
+// client does not have to wait for the next transition to render them.
+func (h *RealtimeHandler) sendSnapshots(session *wsSession, userID uint, jobIDs []string) error {
+	if len(jobIDs) == 0 {
+		return nil
+	}
+
+	jobs, err := h.transcriptionRepo.FindByIDs(jobIDs)
+	if err != nil {
+		logger.Log.WithField("user_id", userID).Errorf("failed to fetch subscribed jobs: %v", err)
+		return nil
+	}
+
+	for i := range jobs {
+		if h.authz.AuthorizeJob(userID, &jobs[i], authz.ActionView) != nil {
+			continue
 		}
-		
-		if err := c.WriteJSON(initialMsg); err != nil {
-			return
+
+		if err := session.writeJSON(progress.Message{
+			JobID:     jobs[i].ID,
+			Status:    string(jobs[i].Status),
+			Timestamp: jobs[i].UpdatedAt.Format(time.RFC3339Nano),
+		}); err != nil {
+			return err
 		}
 	}
 
-	channelName := fmt.Sprintf("job_progress:%s", jobID)
-	pubsub := config.RedisClient.Subscribe(ctx, channelName)
-	defer pubsub.Close()
-	
-	closeCh := make(chan struct{})
+	return nil
+}
+
+// RealtimeStats reports the progress subscribers connected to this instance.
+func (h *RealtimeHandler) RealtimeStats(c *fiber.Ctx) error {
+	return c.JSON(progress.DefaultHub().Stats())
+}
+
+// wsSession serialises writes to a connection, which allows only one writer
+// at a time, and keeps it alive with pings.
+type wsSession struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func newWSSession(c *websocket.Conn) *wsSession {
+	return &wsSession{conn: c}
+}
+
+func (s *wsSession) writeJSON(v interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+
+	return s.conn.WriteJSON(v)
+}
+
+func (s *wsSession) writeText(payload string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+
+	return s.conn.WriteMessage(websocket.TextMessage, []byte(payload))
+}
+
+// run forwards updates that pass allow (nil allows all) while handing client
+// messages to handle, until either side stops. It closes sub and returns only
+// after the forwarding goroutine has exited.
+func (s *wsSession) run(sub *progress.Subscription, allow func(jobID string) bool, handle func([]byte) error) {
+	s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	done := make(chan struct{})
 
 	go func() {
-		defer close(closeCh)
-		
-		ch := pubsub.Channel()
-		for msg := range ch {
-			if err := c.WriteMessage(websocket.TextMessage, []byte(msg.Payload)); err != nil {
-				return
-			}
-		}
+		defer close(done)
+		defer s.conn.Close()
+
+		s.pump(sub, allow)
 	}()
 
 	for {
-		_, _, err := c.ReadMessage()
+		_, data, err := s.conn.ReadMessage()
 		if err != nil {
 			break
 		}
+
+		if err := handle(data); err != nil {
+			break
+		}
+	}
+
+	sub.Close()
+	<-done
+}
+
+func (s *wsSession) pump(sub *progress.Subscription, allow func(jobID string) bool) {
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case update, ok := <-sub.Updates():
+			if !ok {
+				if sub.Evicted() {
+					s.writeJSON(fiber.Map{
+						"status": "error",
+						"error":  "connection too slow, reconnect to resume",
+					})
+				}
+				return
+			}
+
+			if allow != nil && !allow(update.JobID) {
+				continue
+			}
+
+			if err := s.writeText(update.Payload); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// jobFilter decides which jobs a user-wide stream forwards. Until the client
+// subscribes to specific jobs every job passes except unsubscribed ones; a
+// subscribe without job ids goes back to that default.
+type jobFilter struct {
+	mu       sync.Mutex
+	included map[string]bool
+	excluded map[string]bool
+}
+
+func newJobFilter() *jobFilter {
+	return &jobFilter{excluded: make(map[string]bool)}
+}
+
+func (f *jobFilter) allows(jobID string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.included != nil {
+		return f.included[jobID]
+	}
+
+	return !f.excluded[jobID]
+}
+
+func (f *jobFilter) subscribe(jobIDs []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(jobIDs) == 0 {
+		f.included = nil
+		f.excluded = make(map[string]bool)
+		return
+	}
+
+	if f.included == nil {
+		f.included = make(map[string]bool)
+	}
+
+	for _, id := range jobIDs {
+		f.included[id] = true
+		delete(f.excluded, id)
+	}
+}
+
+func (f *jobFilter) unsubscribe(jobIDs []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(jobIDs) == 0 {
+		f.included = make(map[string]bool)
+		return
+	}
+
+	for _, id := range jobIDs {
+		if f.included != nil {
+			delete(f.included, id)
+		} else {
+			f.excluded[id] = true
+		}
 	}
 }
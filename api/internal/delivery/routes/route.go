@@ -2,18 +2,31 @@ package routes
 
 import (
 	"transcribe/internal/delivery/http"
+	"transcribe/internal/domain"
+	"transcribe/internal/queue"
 	"transcribe/pkg/middleware"
 
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupRoutes(app *fiber.App) {
+func SetupRoutes(app *fiber.App, jobQueue queue.Queue) {
 	authHandler := http.NewAuthHandler()
 	userHandler := http.NewUserHandler()
 
-	transcriptionHandler := http.NewTranscriptionHandler()
+	transcriptionHandler := http.NewTranscriptionHandler(jobQueue)
 	realtimeHandler := http.NewRealtimeHandler()
+	queueHandler := http.NewQueueHandler(jobQueue)
+	fileHandler := http.NewFileHandler()
+	uploadHandler := http.NewUploadHandler()
+	webhookHandler := http.NewWebhookHandler()
+	apiKeyHandler := http.NewAPIKeyHandler()
+	adminHandler := http.NewAdminHandler(jobQueue)
+	workspaceHandler := http.NewWorkspaceHandler()
+	shareHandler := http.NewShareHandler()
+	revisionHandler := http.NewRevisionHandler()
+	speakerHandler := http.NewSpeakerHandler()
+	timelineHandler := http.NewTimelineHandler()
 
 	api := app.Group("/api")
 
@@ -24,21 +37,109 @@ func SetupRoutes(app *fiber.App) {
 		})
 	})
 
+	api.Get("/files/*", fileHandler.Download)
+
+	public := api.Group("/public")
+	public.Get("/share/:token", shareHandler.GetSharedTranscript)
+	public.Post("/share/:token", shareHandler.GetSharedTranscript)
+	public.Get("/share/:token/audio", shareHandler.StreamSharedAudio)
+	public.Post("/share/:token/audio", shareHandler.StreamSharedAudio)
+
 	auth := api.Group("/auth")
 	auth.Post("/sign-up", authHandler.SignUp)
 	auth.Post("/sign-in", authHandler.SignIn)
+	auth.Post("/refresh", authHandler.Refresh)
+	auth.Post("/sign-out", middleware.AuthMiddleware, middleware.RequireUserSession, authHandler.SignOut)
 
-	proctected := api.Group("/user", middleware.AuthMiddleware)
+	proctected := api.Group("/user", middleware.AuthMiddleware, middleware.RequireUserSession)
 	proctected.Get("/profile", userHandler.GetProfile)
 	proctected.Put("/profile", userHandler.UpdateProfile)
 
+	read := middleware.RequireScope(domain.ScopeJobsRead)
+	write := middleware.RequireScope(domain.ScopeJobsWrite)
+
 	transcribe := api.Group("/transcribe", middleware.AuthMiddleware)
-	transcribe.Post("/", transcriptionHandler.CreateJob)
-	transcribe.Post("/:job_id/cancel", transcriptionHandler.CancelJob)
-	transcribe.Get("/:job_id", transcriptionHandler.GetJobStatus)
-	transcribe.Get("/", transcriptionHandler.GetUserJobs)
-	transcribe.Delete("/:job_id", transcriptionHandler.DeleteJob)
+	transcribe.Post("/", write, transcriptionHandler.CreateJob)
+	transcribe.Get("/search", read, transcriptionHandler.SearchJobs)
+	transcribe.Post("/cancel", write, transcriptionHandler.CancelJobs)
+	transcribe.Post("/:job_id/cancel", write, transcriptionHandler.CancelJob)
+	transcribe.Put("/:job_id/workspace", write, transcriptionHandler.MoveJob)
+	transcribe.Patch("/:job_id/segments", write, revisionHandler.EditSegments)
+	transcribe.Get("/:job_id/speakers", read, speakerHandler.GetJobSpeakers)
+	transcribe.Put("/:job_id/speakers", write, speakerHandler.UpdateJobSpeakers)
+	transcribe.Get("/:job_id/revisions", read, revisionHandler.ListRevisions)
+	transcribe.Get("/:job_id/revisions/:revision", read, revisionHandler.GetRevision)
+	transcribe.Post("/:job_id/revisions/:revision/restore", write, revisionHandler.RestoreRevision)
+	transcribe.Post("/:job_id/share", write, shareHandler.CreateShareLink)
+	transcribe.Get("/:job_id/share", read, shareHandler.ListShareLinks)
+	transcribe.Delete("/:job_id/share/:share_id", write, shareHandler.RevokeShareLink)
+	transcribe.Get("/:job_id/export", read, transcriptionHandler.ExportJob)
+	transcribe.Get("/:job_id/words", read, transcriptionHandler.GetJobWords)
+	transcribe.Get("/:job_id/events", read, realtimeHandler.StreamEvents)
+	transcribe.Get("/:job_id/events/history", read, realtimeHandler.GetEventHistory)
+	transcribe.Get("/:job_id/timeline", read, timelineHandler.GetJobTimeline)
+	transcribe.Get("/:job_id", read, transcriptionHandler.GetJobStatus)
+	transcribe.Get("/", read, transcriptionHandler.GetUserJobs)
+	transcribe.Delete("/:job_id", write, transcriptionHandler.DeleteJob)
+
+	api.Options("/uploads", uploadHandler.Options)
+	api.Options("/uploads/:upload_id", uploadHandler.Options)
+
+	uploads := api.Group("/uploads", middleware.AuthMiddleware, write, uploadHandler.TusResumable)
+	uploads.Post("/", uploadHandler.Create)
+	uploads.Head("/:upload_id", uploadHandler.Head)
+	uploads.Patch("/:upload_id", uploadHandler.Patch)
+	uploads.Delete("/:upload_id", uploadHandler.Delete)
+
+	webhooks := api.Group("/webhooks", middleware.AuthMiddleware, middleware.RequireUserSession)
+	webhooks.Post("/", webhookHandler.CreateWebhook)
+	webhooks.Get("/", webhookHandler.ListWebhooks)
+	webhooks.Get("/:webhook_id", webhookHandler.GetWebhook)
+	webhooks.Put("/:webhook_id", webhookHandler.UpdateWebhook)
+	webhooks.Delete("/:webhook_id", webhookHandler.DeleteWebhook)
+	webhooks.Post("/:webhook_id/rotate-secret", webhookHandler.RotateSecret)
+	webhooks.Get("/:webhook_id/deliveries", webhookHandler.ListDeliveries)
+	webhooks.Post("/:webhook_id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
+
+	workspaces := api.Group("/workspaces", middleware.AuthMiddleware, middleware.RequireUserSession)
+	workspaces.Post("/", workspaceHandler.CreateWorkspace)
+	workspaces.Get("/", workspaceHandler.ListWorkspaces)
+	workspaces.Get("/:workspace_id", workspaceHandler.GetWorkspace)
+	workspaces.Put("/:workspace_id", workspaceHandler.UpdateWorkspace)
+	workspaces.Delete("/:workspace_id", workspaceHandler.DeleteWorkspace)
+	workspaces.Get("/:workspace_id/members", workspaceHandler.ListMembers)
+	workspaces.Post("/:workspace_id/members", workspaceHandler.AddMember)
+	workspaces.Put("/:workspace_id/members/:user_id", workspaceHandler.UpdateMember)
+	workspaces.Delete("/:workspace_id/members/:user_id", workspaceHandler.RemoveMember)
+
+	speakers := api.Group("/speakers", middleware.AuthMiddleware, middleware.RequireUserSession)
+	speakers.Post("/", speakerHandler.CreateProfile)
+	speakers.Get("/", speakerHandler.ListProfiles)
+	speakers.Put("/:profile_id", speakerHandler.UpdateProfile)
+	speakers.Delete("/:profile_id", speakerHandler.DeleteProfile)
+
+	apiKeys := api.Group("/api-keys", middleware.AuthMiddleware, middleware.RequireUserSession)
+	apiKeys.Post("/", apiKeyHandler.CreateAPIKey)
+	apiKeys.Get("/", apiKeyHandler.ListAPIKeys)
+	apiKeys.Delete("/:key_id", apiKeyHandler.RevokeAPIKey)
+
+	admin := api.Group("/admin", middleware.AuthMiddleware, middleware.RequireUserSession, middleware.RequireRole(domain.RoleAdmin))
+	admin.Get("/users", adminHandler.ListUsers)
+	admin.Get("/users/:user_id", adminHandler.GetUser)
+	admin.Post("/users/:user_id/disable", adminHandler.DisableUser)
+	admin.Post("/users/:user_id/enable", adminHandler.EnableUser)
+	admin.Put("/users/:user_id/role", adminHandler.UpdateUserRole)
+	admin.Delete("/users/:user_id", adminHandler.DeleteUser)
+	admin.Get("/jobs", adminHandler.ListJobs)
+	admin.Post("/jobs/:job_id/cancel", adminHandler.CancelJob)
+	admin.Delete("/jobs/:job_id", adminHandler.DeleteJob)
+	admin.Get("/queue", queueHandler.QueueStats)
+	admin.Get("/queue/dead", queueHandler.ListDeadJobs)
+	admin.Post("/queue/dead/:job_id/replay", queueHandler.ReplayDeadJob)
+	admin.Delete("/queue/dead/:job_id", queueHandler.DiscardDeadJob)
+	admin.Get("/realtime", realtimeHandler.RealtimeStats)
 
-	ws := api.Group("/ws", middleware.AuthMiddleware, realtimeHandler.WSUpgrade)
+	ws := api.Group("/ws", middleware.AuthMiddleware, read, realtimeHandler.WSUpgrade)
 	ws.Get("/job/:job_id", websocket.New(realtimeHandler.ListenForProgress))
+	ws.Get("/jobs", websocket.New(realtimeHandler.ListenForUserProgress))
 }
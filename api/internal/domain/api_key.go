@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+const (
+	ScopeJobsRead  = "jobs:read"
+	ScopeJobsWrite = "jobs:write"
+
+	APIKeyPrefix = "tk_"
+)
+
+var APIKeyScopes = []string{
+	ScopeJobsRead,
+	ScopeJobsWrite,
+}
+
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix     string     `gorm:"type:varchar(20);not null" json:"prefix"`
+	KeyHash    string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Scopes     string     `gorm:"type:varchar(255);not null" json:"-"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APIKeyResponse struct {
+	APIKey
+	Scopes []string `json:"scopes"`
+	Key    string   `json:"key,omitempty"`
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+const (
+	JobEventActorUser   = "user"
+	JobEventActorAdmin  = "admin"
+	JobEventActorWorker = "worker"
+	JobEventActorSystem = "system"
+)
+
+// JobEvent is one entry in a job's timeline: a status change or a processing
+// stage reported by the worker. ActorID is set for user and admin actions.
+type JobEvent struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	JobID     string    `gorm:"type:varchar(36);not null;index:idx_job_event_job_time,priority:1" json:"job_id"`
+	Stage     string    `gorm:"type:varchar(30);not null" json:"stage"`
+	Progress  *float64  `json:"progress,omitempty"`
+	Message   string    `gorm:"type:text" json:"message,omitempty"`
+	Actor     string    `gorm:"type:varchar(20);not null" json:"actor"`
+	ActorID   *uint     `json:"actor_id,omitempty"`
+	CreatedAt time.Time `gorm:"index:idx_job_event_job_time,priority:2" json:"created_at"`
+}
+
+type JobEventResponse struct {
+	JobEvent
+	Duration *float64 `json:"duration,omitempty"`
+}
+
+type StageDuration struct {
+	Stage    string  `json:"stage"`
+	Duration float64 `json:"duration"`
+	Count    int     `json:"count"`
+}
+
+type TimelineResponse struct {
+	JobID    string             `json:"job_id"`
+	Status   JobStatus          `json:"status"`
+	Events   []JobEventResponse `json:"events"`
+	Stages   []StageDuration    `json:"stages"`
+	Duration float64            `json:"duration"`
+}
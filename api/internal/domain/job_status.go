@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// JobStatus is the persisted state of a transcription job. Worker stages such
+// as loading_audio or transcribing are progress events, not statuses.
+type JobStatus string
+
+const (
+	JobStatusQueued     JobStatus = "queued"
+	JobStatusProcessing JobStatus = "processing"
+	JobStatusRetrying   JobStatus = "retrying"
+	JobStatusDone       JobStatus = "done"
+	JobStatusFailed     JobStatus = "failed"
+	JobStatusCancelling JobStatus = "cancelling"
+	JobStatusCancelled  JobStatus = "cancelled"
+)
+
+var ErrIllegalTransition = errors.New("illegal job status transition")
+
+// jobTransitions lists the statuses each status may move to. A failed job
+// can only leave its state when an admin replays it from the dead-letter
+// queue; done and cancelled are final. A cancellation is only requested by
+// moving to cancelling, and becomes cancelled once the worker acknowledges it
+// or no worker holds the job.
+var jobTransitions = map[JobStatus][]JobStatus{
+	JobStatusQueued:     {JobStatusProcessing, JobStatusRetrying, JobStatusFailed, JobStatusCancelling},
+	JobStatusProcessing: {JobStatusDone, JobStatusRetrying, JobStatusFailed, JobStatusCancelling},
+	JobStatusRetrying:   {JobStatusQueued, JobStatusProcessing, JobStatusFailed, JobStatusCancelling},
+	JobStatusCancelling: {JobStatusCancelled},
+	JobStatusFailed:     {JobStatusQueued},
+	JobStatusDone:       {},
+	JobStatusCancelled:  {},
+}
+
+// TransitionError reports a status change the state machine does not allow.
+// It matches ErrIllegalTransition with errors.Is.
+type TransitionError struct {
+	From JobStatus
+	To   JobStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot move job from %s to %s", e.From, e.To)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrIllegalTransition
+}
+
+func (s JobStatus) Valid() bool {
+	_, ok := jobTransitions[s]
+	return ok
+}
+
+// IsTerminal reports whether the job has stopped running. A failed job is
+// terminal even though it may be replayed.
+func (s JobStatus) IsTerminal() bool {
+	return s == JobStatusDone || s == JobStatusFailed || s == JobStatusCancelled
+}
+
+func (s JobStatus) CanTransitionTo(next JobStatus) bool {
+	for _, allowed := range jobTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// JobStatusesBefore returns every status that may move to next.
+func JobStatusesBefore(next JobStatus) []JobStatus {
+	var from []JobStatus
+
+	for _, status := range JobStatusList {
+		if status.CanTransitionTo(next) {
+			from = append(from, status)
+		}
+	}
+
+	return from
+}
+
+var JobStatusList = []JobStatus{
+	JobStatusQueued,
+	JobStatusProcessing,
+	JobStatusRetrying,
+	JobStatusDone,
+	JobStatusFailed,
+	JobStatusCancelling,
+	JobStatusCancelled,
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestJobStatusesBefore(t *testing.T) {
+	tests := []struct {
+		next JobStatus
+		want []JobStatus
+	}{
+		{next: JobStatusQueued, want: []JobStatus{JobStatusRetrying, JobStatusFailed}},
+		{next: JobStatusProcessing, want: []JobStatus{JobStatusQueued, JobStatusRetrying}},
+		{next: JobStatusRetrying, want: []JobStatus{JobStatusQueued, JobStatusProcessing}},
+		{next: JobStatusDone, want: []JobStatus{JobStatusProcessing}},
+		{next: JobStatusFailed, want: []JobStatus{JobStatusQueued, JobStatusProcessing, JobStatusRetrying}},
+		{next: JobStatusCancelling, want: []JobStatus{JobStatusQueued, JobStatusProcessing, JobStatusRetrying}},
+		{next: JobStatusCancelled, want: []JobStatus{JobStatusCancelling}},
+		{next: JobStatus("transcribing"), want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.next), func(t *testing.T) {
+			if got := JobStatusesBefore(tt.next); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("JobStatusesBefore(%s) = %v, want %v", tt.next, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobStatusListCoversTransitions(t *testing.T) {
+	if len(JobStatusList) != len(jobTransitions) {
+		t.Fatalf("JobStatusList has %d statuses, jobTransitions has %d", len(JobStatusList), len(jobTransitions))
+	}
+
+	for _, status := range JobStatusList {
+		if !status.Valid() {
+			t.Fatalf("%s is listed but has no transitions", status)
+		}
+	}
+}
+
+func TestJobStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to JobStatus
+		want     bool
+	}{
+		{from: JobStatusQueued, to: JobStatusProcessing, want: true},
+		{from: JobStatusProcessing, to: JobStatusDone, want: true},
+		{from: JobStatusCancelling, to: JobStatusCancelled, want: true},
+		{from: JobStatusFailed, to: JobStatusQueued, want: true},
+		{from: JobStatusDone, to: JobStatusQueued, want: false},
+		{from: JobStatusCancelled, to: JobStatusQueued, want: false},
+		{from: JobStatusCancelling, to: JobStatusDone, want: false},
+		{from: JobStatusQueued, to: JobStatusCancelled, want: false},
+		{from: JobStatusProcessing, to: JobStatusProcessing, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+				t.Fatalf("%s.CanTransitionTo(%s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransitionErrorIs(t *testing.T) {
+	var err error = &TransitionError{From: JobStatusDone, To: JobStatusCancelling}
+
+	if !errors.Is(err, ErrIllegalTransition) {
+		t.Fatal("TransitionError does not match ErrIllegalTransition")
+	}
+
+	if got, want := err.Error(), "cannot move job from done to cancelling"; got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+
+	OutboxTopicEnqueueJob = "enqueue_job"
+)
+
+type OutboxMessage struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Topic       string     `gorm:"type:varchar(100);not null" json:"topic"`
+	AggregateID string     `gorm:"type:varchar(36);not null;index" json:"aggregate_id"`
+	Payload     string     `gorm:"type:longtext;not null" json:"payload"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'pending';index:idx_outbox_dispatch,priority:1" json:"status"`
+	AvailableAt time.Time  `gorm:"not null;index:idx_outbox_dispatch,priority:2" json:"available_at"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	LastError   string     `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+const (
+	OpEditText       = "edit_text"
+	OpSplit          = "split"
+	OpMerge          = "merge"
+	OpRetime         = "retime"
+	OpRelabelSpeaker = "relabel_speaker"
+
+	RevisionActionOriginal = "original"
+	RevisionActionEdit     = "edit"
+	RevisionActionRestore  = "restore"
+)
+
+type TranscriptRevision struct {
+	ID           uint      `gorm:"primaryKey" json:"-"`
+	JobID        string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_revision_job_number,priority:1" json:"job_id"`
+	Number       int       `gorm:"not null;uniqueIndex:idx_revision_job_number,priority:2" json:"revision"`
+	AuthorID     *uint     `json:"author_id,omitempty"`
+	Action       string    `gorm:"type:varchar(20);not null" json:"action"`
+	RestoredFrom *int      `json:"restored_from,omitempty"`
+	Operations   string    `gorm:"type:text" json:"-"`
+	Segments     string    `gorm:"type:longtext" json:"-"`
+	Text         string    `gorm:"type:longtext" json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// SegmentOperation is one transcript edit. Operations in a request are
+// applied in order and segment ids are renumbered after each one.
+type SegmentOperation struct {
+	Op         string   `json:"op"`
+	SegmentID  *int     `json:"segment_id,omitempty"`
+	SegmentIDs []int    `json:"segment_ids,omitempty"`
+	Text       string   `json:"text,omitempty"`
+	Texts      []string `json:"texts,omitempty"`
+	At         float64  `json:"at,omitempty"`
+	Start      *float64 `json:"start,omitempty"`
+	End        *float64 `json:"end,omitempty"`
+	From       string   `json:"from,omitempty"`
+	Speaker    *string  `json:"speaker,omitempty"`
+}
+
+type EditSegmentsRequest struct {
+	Revision   *int               `json:"revision"`
+	Operations []SegmentOperation `json:"operations"`
+}
+
+type RevisionResponse struct {
+	TranscriptRevision
+	Operations []SegmentOperation `json:"operations,omitempty"`
+	Text       string             `json:"text,omitempty"`
+	Segments   []Segment          `json:"segments,omitempty"`
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+type TranscriptSegment struct {
+	ID        uint    `gorm:"primaryKey" json:"-"`
+	JobID     string  `gorm:"type:varchar(36);not null;index" json:"job_id"`
+	UserID    uint    `gorm:"not null;index" json:"-"`
+	SegmentID int     `gorm:"not null" json:"segment_id"`
+	StartTime float64 `gorm:"not null" json:"start"`
+	EndTime   float64 `gorm:"not null" json:"end"`
+	Speaker   string  `gorm:"type:varchar(100);index" json:"speaker,omitempty"`
+	Text      string  `gorm:"type:text;index:idx_transcript_segment_text,class:FULLTEXT" json:"text"`
+}
+
+type SearchHit struct {
+	SegmentID    int     `json:"segment_id"`
+	StartTime    float64 `json:"start"`
+	EndTime      float64 `json:"end"`
+	Speaker      string  `json:"speaker,omitempty"`
+	SpeakerLabel string  `json:"speaker_label,omitempty"`
+	Text         string  `json:"text"`
+	Snippet      string  `json:"snippet"`
+}
+
+type SearchResult struct {
+	JobID     string      `json:"job_id"`
+	FileName  string      `json:"file_name"`
+	Status    JobStatus   `json:"status"`
+	Score     float64     `json:"score"`
+	CreatedAt time.Time   `json:"created_at"`
+	Hits      []SearchHit `json:"hits"`
+}
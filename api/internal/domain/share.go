@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+const ShareTokenPrefix = "sh_"
+
+type ShareLink struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	JobID          string     `gorm:"type:varchar(36);not null;index" json:"job_id"`
+	CreatedBy      uint       `gorm:"not null" json:"created_by"`
+	Prefix         string     `gorm:"type:varchar(20);not null" json:"prefix"`
+	TokenHash      string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	PasswordHash   string     `gorm:"type:varchar(100)" json:"-"`
+	AllowAudio     bool       `gorm:"not null;default:false" json:"allow_audio"`
+	AccessCount    int64      `gorm:"not null;default:0" json:"access_count"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type CreateShareLinkRequest struct {
+	Password   string     `json:"password"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	AllowAudio bool       `json:"allow_audio"`
+}
+
+type SharePasswordRequest struct {
+	Password string `json:"password" form:"password"`
+}
+
+type ShareLinkResponse struct {
+	ShareLink
+	HasPassword bool   `json:"has_password"`
+	Token       string `json:"token,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+type SharedTranscript struct {
+	FileName    string     `json:"file_name"`
+	Duration    float64    `json:"duration,omitempty"`
+	Language    string     `json:"language,omitempty"`
+	Text        string     `json:"text"`
+	Segments    []Segment  `json:"segments"`
+	AudioURL    string     `json:"audio_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+type SpeakerProfile struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_speaker_profile_name,priority:1" json:"user_id"`
+	Name      string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_speaker_profile_name,priority:2" json:"name"`
+	Notes     string    `gorm:"type:varchar(255)" json:"notes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// JobSpeaker maps a diarization label such as SPEAKER_00 to a display name
+// for one job. Name is kept in sync with the linked profile, if any.
+type JobSpeaker struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	JobID     string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_job_speaker_label,priority:1" json:"-"`
+	Label     string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_job_speaker_label,priority:2" json:"label"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	ProfileID *uint     `gorm:"index" json:"profile_id,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SpeakerProfileRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Notes string `json:"notes"`
+}
+
+type SpeakerAssignment struct {
+	Name      string `json:"name"`
+	ProfileID *uint  `json:"profile_id"`
+}
+
+type UpdateJobSpeakersRequest struct {
+	Speakers map[string]SpeakerAssignment `json:"speakers"`
+}
+
+type JobSpeakerResponse struct {
+	Label     string  `json:"label"`
+	Name      string  `json:"name,omitempty"`
+	ProfileID *uint   `json:"profile_id,omitempty"`
+	Segments  int     `json:"segments"`
+	Duration  float64 `json:"duration"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+type RefreshToken struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	UserID          uint       `gorm:"not null;index" json:"user_id"`
+	FamilyID        string     `gorm:"type:varchar(36);not null;index" json:"family_id"`
+	TokenHash       string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	AccessTokenID   string     `gorm:"type:varchar(36)" json:"-"`
+	AccessExpiresAt time.Time  `json:"-"`
+	ExpiresAt       time.Time  `gorm:"not null;index" json:"expires_at"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type SignOutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	All          bool   `json:"all"`
+}
@@ -1,47 +1,182 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type TranscriptionJob struct {
-	ID          string         `gorm:"type:varchar(36);primaryKey" json:"job_id"`
-	UserID      uint           `gorm:"not null;index" json:"user_id"`
-	User        User           `gorm:"foreignKey:UserID" json:"-"`
-	FileName    string         `gorm:"type:varchar(255);not null" json:"file_name"`
-	FilePath    string         `gorm:"type:varchar(500);not null" json:"file_path"`
-	FileSize    int64          `gorm:"not null" json:"file_size"`
-	Duration    float64        `gorm:"default:0" json:"duration,omitempty"`
-	Status      string         `gorm:"type:varchar(20);not null;default:'queued';index" json:"status"`
-	Text        string         `gorm:"type:text" json:"text,omitempty"`
-	Segments    string         `gorm:"type:longtext" json:"-"`
-	ErrorMsg    string         `gorm:"type:text" json:"error_message,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
-	CompletedAt *time.Time     `json:"completed_at,omitempty"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID          string               `gorm:"type:varchar(36);primaryKey" json:"job_id"`
+	UserID      uint                 `gorm:"not null;index" json:"user_id"`
+	User        User                 `gorm:"foreignKey:UserID" json:"-"`
+	WorkspaceID *uint                `gorm:"index" json:"workspace_id,omitempty"`
+	FileName    string               `gorm:"type:varchar(255);not null" json:"file_name"`
+	FilePath    string               `gorm:"type:varchar(500);not null" json:"file_path"`
+	FileSize    int64                `gorm:"not null" json:"file_size"`
+	Duration    float64              `gorm:"default:0" json:"duration,omitempty"`
+	Status      JobStatus            `gorm:"type:varchar(20);not null;default:'queued';index" json:"status"`
+	Text        string               `gorm:"type:text" json:"text,omitempty"`
+	Segments    string               `gorm:"type:longtext" json:"-"`
+	Revision    int                  `gorm:"not null;default:0" json:"revision"`
+	ErrorMsg    string               `gorm:"type:text" json:"error_message,omitempty"`
+	Options     TranscriptionOptions `gorm:"embedded;embeddedPrefix:opt_" json:"options"`
+	Attempts    int                  `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts int                  `gorm:"not null;default:3" json:"max_attempts"`
+	NextRetryAt *time.Time           `json:"next_retry_at,omitempty"`
+	IndexedAt   *time.Time           `gorm:"index" json:"-"`
+	CreatedAt   time.Time            `json:"created_at"`
+	CompletedAt *time.Time           `json:"completed_at,omitempty"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt       `gorm:"index" json:"-"`
+}
+
+type TranscriptionOptions struct {
+	Language    string `gorm:"type:varchar(10)" json:"language,omitempty"`
+	Model       string `gorm:"type:varchar(20)" json:"model,omitempty"`
+	Task        string `gorm:"type:varchar(20);default:'transcribe'" json:"task,omitempty"`
+	Diarization string `gorm:"type:varchar(20)" json:"diarization,omitempty"`
+	NumSpeakers int    `gorm:"default:0" json:"num_speakers,omitempty"`
+}
+
+var (
+	AllowedModels      = []string{"tiny", "base", "small", "medium", "large"}
+	AllowedTasks       = []string{"transcribe", "translate"}
+	AllowedDiarization = []string{"none", "simple", "resemblyzer", "pyannote"}
+)
+
+func (o *TranscriptionOptions) Normalize() {
+	o.Language = strings.ToLower(strings.TrimSpace(o.Language))
+	o.Model = strings.ToLower(strings.TrimSpace(o.Model))
+	o.Task = strings.ToLower(strings.TrimSpace(o.Task))
+	o.Diarization = strings.ToLower(strings.TrimSpace(o.Diarization))
+
+	if o.Language == "auto" {
+		o.Language = ""
+	}
+
+	if o.Task == "" {
+		o.Task = "transcribe"
+	}
+}
+
+func (o TranscriptionOptions) Validate() error {
+	if o.Language != "" {
+		if len(o.Language) < 2 || len(o.Language) > 3 {
+			return fmt.Errorf("invalid language code: %s", o.Language)
+		}
+
+		for _, r := range o.Language {
+			if r < 'a' || r > 'z' {
+				return fmt.Errorf("invalid language code: %s", o.Language)
+			}
+		}
+	}
+
+	if o.Model != "" && !contains(AllowedModels, o.Model) {
+		return fmt.Errorf("invalid model. Allowed: %s", strings.Join(AllowedModels, ", "))
+	}
+
+	if !contains(AllowedTasks, o.Task) {
+		return fmt.Errorf("invalid task. Allowed: %s", strings.Join(AllowedTasks, ", "))
+	}
+
+	if o.Diarization != "" && !contains(AllowedDiarization, o.Diarization) {
+		return fmt.Errorf("invalid diarization. Allowed: %s", strings.Join(AllowedDiarization, ", "))
+	}
+
+	if o.NumSpeakers < 0 || o.NumSpeakers > 20 {
+		return errors.New("num_speakers must be between 0 and 20")
+	}
+
+	if o.NumSpeakers > 0 && (o.Diarization == "" || o.Diarization == "none") {
+		return errors.New("num_speakers requires a diarization method")
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
 }
 
 type Segment struct {
-	ID        int     `json:"id"`
-	StartTime float64 `json:"start"`
-	EndTime   float64 `json:"end"`
-	Text      string  `json:"text"`
-	Speaker   string  `json:"speaker,omitempty"`
+	ID           int     `json:"id"`
+	StartTime    float64 `json:"start"`
+	EndTime      float64 `json:"end"`
+	Text         string  `json:"text"`
+	Speaker      string  `json:"speaker,omitempty"`
+	SpeakerLabel string  `json:"speaker_label,omitempty"`
+	Words        []Word  `json:"words,omitempty"`
+}
+
+type Word struct {
+	Text        string  `json:"text"`
+	Start       float64 `json:"start"`
+	End         float64 `json:"end"`
+	Probability float64 `json:"probability"`
+}
+
+type WordResponse struct {
+	Word
+	SegmentID    int    `json:"segment_id"`
+	Speaker      string `json:"speaker,omitempty"`
+	SpeakerLabel string `json:"speaker_label,omitempty"`
 }
 
 type TranscriptionResponse struct {
-	JobID       string     `json:"job_id"`
-	Status      string     `json:"status"`
-	Message     string     `json:"message,omitempty"`
-	Text        string     `json:"text,omitempty"`
-	Segments    []Segment  `json:"segments,omitempty"`
-	Duration    float64    `json:"duration,omitempty"`
-	FileName    string     `json:"file_name,omitempty"`
-	FileSize    int64      `json:"file_size,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
-	ErrorMsg    string     `json:"error_message,omitempty"`
+	JobID       string                `json:"job_id"`
+	UserID      uint                  `json:"user_id,omitempty"`
+	WorkspaceID *uint                 `json:"workspace_id,omitempty"`
+	Status      JobStatus             `json:"status"`
+	Message     string                `json:"message,omitempty"`
+	Text        string                `json:"text,omitempty"`
+	Segments    []Segment             `json:"segments,omitempty"`
+	Revision    int                   `json:"revision,omitempty"`
+	Duration    float64               `json:"duration,omitempty"`
+	FileName    string                `json:"file_name,omitempty"`
+	FileSize    int64                 `json:"file_size,omitempty"`
+	Options     *TranscriptionOptions `json:"options,omitempty"`
+	Attempts    int                   `json:"attempts,omitempty"`
+	MaxAttempts int                   `json:"max_attempts,omitempty"`
+	NextRetryAt *time.Time            `json:"next_retry_at,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	CompletedAt *time.Time            `json:"completed_at,omitempty"`
+	ErrorMsg    string                `json:"error_message,omitempty"`
+}
+
+// JobStreamCommand is sent by clients of the user-wide job stream to choose
+// which jobs they receive events for.
+type JobStreamCommand struct {
+	Action string   `json:"action"`
+	JobIDs []string `json:"job_ids"`
+}
+
+type CancelJobsRequest struct {
+	JobIDs []string `json:"job_ids"`
+}
+
+// CancelJobResult reports the outcome of one job in a bulk cancellation.
+// Status is the job's status after the request, when the job was found.
+type CancelJobResult struct {
+	JobID  string    `json:"job_id"`
+	Status JobStatus `json:"status,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+type JobFilter struct {
+	UserID   uint
+	Status   JobStatus
+	FileName string
+	From     *time.Time
+	To       *time.Time
 }
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+type Upload struct {
+	ID          string        `gorm:"type:varchar(36);primaryKey" json:"upload_id"`
+	UserID      uint          `gorm:"not null;index" json:"user_id"`
+	WorkspaceID *uint         `json:"workspace_id,omitempty"`
+	FileName    string        `gorm:"type:varchar(255);not null" json:"file_name"`
+	ContentType string        `gorm:"type:varchar(100)" json:"content_type,omitempty"`
+	Length      int64         `gorm:"not null" json:"length"`
+	Offset      int64         `gorm:"column:byte_offset;not null;default:0" json:"offset"`
+	Options     string        `gorm:"type:text" json:"-"`
+	JobID       *string       `gorm:"type:varchar(36)" json:"job_id,omitempty"`
+	ExpiresAt   time.Time     `gorm:"not null;index" json:"expires_at"`
+	CompletedAt *time.Time    `json:"completed_at,omitempty"`
+	Chunks      []UploadChunk `gorm:"foreignKey:UploadID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+type UploadChunk struct {
+	ID         uint   `gorm:"primaryKey"`
+	UploadID   string `gorm:"type:varchar(36);not null;uniqueIndex:idx_upload_chunk_offset,priority:1"`
+	Offset     int64  `gorm:"column:byte_offset;not null;uniqueIndex:idx_upload_chunk_offset,priority:2"`
+	Size       int64  `gorm:"not null"`
+	StorageKey string `gorm:"type:varchar(500);not null"`
+}
@@ -6,14 +6,33 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+var Roles = []string{RoleUser, RoleAdmin}
+
 type User struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Name      string         `gorm:"type:varchar(255);not null" json:"name"`
-	Email     string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
-	Password  string         `gorm:"type:varchar(255);not null" json:"-"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	Name       string         `gorm:"type:varchar(255);not null" json:"name"`
+	Email      string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	Password   string         `gorm:"type:varchar(255);not null" json:"-"`
+	Role       string         `gorm:"type:varchar(20);not null;default:'user';index" json:"role"`
+	DisabledAt *time.Time     `json:"disabled_at,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type UserFilter struct {
+	Query    string
+	Role     string
+	Disabled *bool
+}
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" validate:"required"`
 }
 
 type RegisterRequest struct {
@@ -28,13 +47,16 @@ type LoginRequest struct {
 }
 
 type AuthResponse struct {
-	Message string      `json:"message"`
-	Token   string      `json:"token"`
-	User    UserProfile `json:"user"`
+	Message      string      `json:"message"`
+	Token        string      `json:"token"`
+	RefreshToken string      `json:"refresh_token"`
+	ExpiresIn    int64       `json:"expires_in"`
+	User         UserProfile `json:"user"`
 }
 
 type UserProfile struct {
 	ID    uint   `json:"id"`
 	Name  string `json:"name"`
 	Email string `json:"email"`
+	Role  string `json:"role"`
 }
@@ -0,0 +1,101 @@
+package domain
+
+import "time"
+
+const (
+	WebhookEventProcessing = "job.processing"
+	WebhookEventDone       = "job.done"
+	WebhookEventFailed     = "job.failed"
+	WebhookEventCancelled  = "job.cancelled"
+
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+var WebhookEvents = []string{
+	WebhookEventProcessing,
+	WebhookEventDone,
+	WebhookEventFailed,
+	WebhookEventCancelled,
+}
+
+func WebhookEventForStatus(status string) (string, bool) {
+	switch JobStatus(status) {
+	case JobStatusProcessing:
+		return WebhookEventProcessing, true
+	case JobStatusDone:
+		return WebhookEventDone, true
+	case JobStatusFailed:
+		return WebhookEventFailed, true
+	case JobStatusCancelled:
+		return WebhookEventCancelled, true
+	}
+
+	return "", false
+}
+
+type Webhook struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	URL         string    `gorm:"type:varchar(2048);not null" json:"url"`
+	Description string    `gorm:"type:varchar(255)" json:"description,omitempty"`
+	Secret      string    `gorm:"type:varchar(100);not null" json:"-"`
+	Events      string    `gorm:"type:varchar(255);not null" json:"-"`
+	Active      bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	WebhookID      uint       `gorm:"not null;index" json:"webhook_id"`
+	JobID          string     `gorm:"type:varchar(36);not null;index" json:"job_id"`
+	Event          string     `gorm:"type:varchar(50);not null" json:"event"`
+	DedupeKey      *string    `gorm:"type:varchar(150);uniqueIndex" json:"-"`
+	Payload        string     `gorm:"type:longtext;not null" json:"payload"`
+	Status         string     `gorm:"type:varchar(20);not null;default:'pending';index:idx_webhook_dispatch,priority:1" json:"status"`
+	NextAttemptAt  time.Time  `gorm:"not null;index:idx_webhook_dispatch,priority:2" json:"next_attempt_at"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	LastError      string     `gorm:"type:text" json:"last_error,omitempty"`
+	RedeliveryOf   *uint      `json:"redelivery_of,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+type WebhookPayload struct {
+	ID        uint       `json:"id"`
+	Event     string     `json:"event"`
+	CreatedAt time.Time  `json:"created_at"`
+	Job       WebhookJob `json:"job"`
+}
+
+type WebhookJob struct {
+	JobID       string     `json:"job_id"`
+	Status      string     `json:"status"`
+	FileName    string     `json:"file_name"`
+	Duration    float64    `json:"duration,omitempty"`
+	ErrorMsg    string     `json:"error_message,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+type WebhookResponse struct {
+	Webhook
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+type CreateWebhookRequest struct {
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	Events      []string `json:"events"`
+}
+
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url"`
+	Description *string  `json:"description"`
+	Events      []string `json:"events"`
+	Active      *bool    `json:"active"`
+}
@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+const (
+	WorkspaceRoleOwner  = "owner"
+	WorkspaceRoleEditor = "editor"
+	WorkspaceRoleViewer = "viewer"
+)
+
+var WorkspaceRoles = []string{
+	WorkspaceRoleOwner,
+	WorkspaceRoleEditor,
+	WorkspaceRoleViewer,
+}
+
+type Workspace struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	CreatedBy uint      `gorm:"not null" json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WorkspaceMember struct {
+	ID          uint      `gorm:"primaryKey" json:"-"`
+	WorkspaceID uint      `gorm:"not null;uniqueIndex:idx_workspace_member,priority:1" json:"workspace_id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_workspace_member,priority:2;index" json:"user_id"`
+	User        User      `gorm:"foreignKey:UserID" json:"-"`
+	Workspace   Workspace `gorm:"foreignKey:WorkspaceID" json:"-"`
+	Role        string    `gorm:"type:varchar(20);not null" json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type JobScope struct {
+	UserID       uint
+	WorkspaceIDs []uint
+}
+
+type WorkspaceRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type AddMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+type WorkspaceResponse struct {
+	Workspace
+	Role    string           `json:"role"`
+	Members []MemberResponse `json:"members,omitempty"`
+}
+
+type MemberResponse struct {
+	UserID   uint      `json:"user_id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type MoveJobRequest struct {
+	WorkspaceID *uint `json:"workspace_id"`
+}
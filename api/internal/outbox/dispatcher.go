@@ -0,0 +1,115 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+	"transcribe/internal/queue"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+)
+
+const (
+	batchSize       = 100
+	cleanupInterval = time.Hour
+)
+
+type Dispatcher struct {
+	jobQueue   queue.Queue
+	outboxRepo *repository.OutboxRepository
+	interval   time.Duration
+	retention  time.Duration
+}
+
+func NewDispatcher(jobQueue queue.Queue) *Dispatcher {
+	return &Dispatcher{
+		jobQueue:   jobQueue,
+		outboxRepo: repository.NewOutboxRepository(),
+		interval:   config.AppConfig.OutboxPollInterval,
+		retention:  config.AppConfig.OutboxRetention,
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	cleanup := time.NewTicker(cleanupInterval)
+	defer cleanup.Stop()
+
+	logger.Log.Infof("outbox dispatcher started (interval %s)", d.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Log.Info("outbox dispatcher stopped")
+			return
+		case <-ticker.C:
+			d.dispatch(ctx)
+		case <-cleanup.C:
+			d.cleanup()
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) {
+	for {
+		sent, err := d.outboxRepo.ProcessPending(batchSize, func(msg *domain.OutboxMessage) error {
+			return d.publish(ctx, msg)
+		})
+
+		if err != nil {
+			logger.Log.Errorf("failed to dispatch outbox messages: %v", err)
+			return
+		}
+
+		if sent > 0 {
+			logger.Log.Debugf("dispatched %d outbox messages", sent)
+		}
+
+		if sent < batchSize {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) publish(ctx context.Context, msg *domain.OutboxMessage) error {
+	log := logger.Log.WithField("job_id", msg.AggregateID)
+
+	switch msg.Topic {
+	case domain.OutboxTopicEnqueueJob:
+		var job queue.Job
+
+		if err := json.Unmarshal([]byte(msg.Payload), &job); err != nil {
+			log.Errorf("invalid outbox payload: %v", err)
+			return err
+		}
+
+		if err := d.jobQueue.Enqueue(ctx, &job); err != nil {
+			log.Warnf("failed to publish job to queue: %v", err)
+			return err
+		}
+
+		log.Info("job published to queue")
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown outbox topic: %s", msg.Topic)
+}
+
+func (d *Dispatcher) cleanup() {
+	removed, err := d.outboxRepo.DeleteSentBefore(time.Now().Add(-d.retention))
+
+	if err != nil {
+		logger.Log.Errorf("failed to clean up sent outbox messages: %v", err)
+		return
+	}
+
+	if removed > 0 {
+		logger.Log.Infof("removed %d sent outbox messages", removed)
+	}
+}
@@ -0,0 +1,206 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+	"transcribe/config"
+	"transcribe/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// ClientBuffer is how many updates a subscriber may fall behind before the
+	// hub evicts it.
+	ClientBuffer = 64
+
+	hubRetryInterval = 2 * time.Second
+	hubReadyTimeout  = 5 * time.Second
+)
+
+var ErrHubNotRunning = errors.New("progress hub is not running")
+
+// Hub fans progress out to every in-process subscriber over a single Redis
+// connection holding pattern subscriptions for job and user channels.
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[string]map[*Subscription]struct{}
+	ready   chan struct{}
+	started sync.Once
+	evicted atomic.Uint64
+}
+
+type HubStats struct {
+	Clients  int    `json:"clients"`
+	Channels int    `json:"channels"`
+	Evicted  uint64 `json:"evicted"`
+}
+
+var defaultHub = NewHub()
+
+// DefaultHub is the hub Follow and SubscribeUser register with. Its Run loop
+// has to be started once per process.
+func DefaultHub() *Hub {
+	return defaultHub
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		clients: make(map[string]map[*Subscription]struct{}),
+		ready:   make(chan struct{}),
+	}
+}
+
+func (h *Hub) Run(ctx context.Context) {
+	pubsub := config.RedisClient.PSubscribe(ctx, ChannelPrefix+"*", UserChannelPrefix+"*")
+	defer pubsub.Close()
+
+	// Wait for both patterns to be confirmed before accepting subscribers.
+	for {
+		msg, err := pubsub.Receive(ctx)
+
+		if err == nil {
+			if confirmed, ok := msg.(*redis.Subscription); ok && confirmed.Count == 2 {
+				break
+			}
+
+			continue
+		}
+
+		logger.Log.Errorf("progress hub failed to subscribe: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(hubRetryInterval):
+		}
+	}
+
+	h.started.Do(func() { close(h.ready) })
+
+	logger.Log.Info("progress hub started")
+
+	ch := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Log.Info("progress hub stopped")
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			h.dispatch(msg.Channel, msg.Payload)
+		}
+	}
+}
+
+func (h *Hub) Stats() HubStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	stats := HubStats{
+		Channels: len(h.clients),
+		Evicted:  h.evicted.Load(),
+	}
+
+	for _, subs := range h.clients {
+		stats.Clients += len(subs)
+	}
+
+	return stats
+}
+
+// register waits for the hub to be subscribed, so a caller reading state
+// afterwards cannot miss an update published in between.
+func (h *Hub) register(ctx context.Context, channel, afterID string) (*Subscription, error) {
+	select {
+	case <-h.ready:
+	case <-time.After(hubReadyTimeout):
+		return nil, ErrHubNotRunning
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	sub := &Subscription{
+		hub:     h,
+		channel: channel,
+		afterID: afterID,
+		updates: make(chan Update, ClientBuffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[channel] == nil {
+		h.clients[channel] = make(map[*Subscription]struct{})
+	}
+
+	h.clients[channel][sub] = struct{}{}
+
+	return sub, nil
+}
+
+// remove must be called with h.mu held.
+func (h *Hub) remove(sub *Subscription) {
+	subs, ok := h.clients[sub.channel]
+
+	if !ok {
+		return
+	}
+
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	close(sub.updates)
+
+	if len(subs) == 0 {
+		delete(h.clients, sub.channel)
+	}
+}
+
+func (h *Hub) unregister(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(sub)
+}
+
+// dispatch never blocks on a subscriber: one whose buffer is full is dropped
+// and sees its Updates channel closed.
+func (h *Hub) dispatch(channel, payload string) {
+	var update Update
+
+	if err := json.Unmarshal([]byte(payload), &update.Message); err != nil {
+		return
+	}
+
+	update.Payload = payload
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.clients[channel] {
+		if sub.afterID != "" && update.EventID != "" && !EventAfter(update.EventID, sub.afterID) {
+			continue
+		}
+
+		select {
+		case sub.updates <- update:
+		default:
+			sub.evicted.Store(true)
+			h.remove(sub)
+			h.evicted.Add(1)
+
+			logger.Log.WithField("channel", channel).Warn("evicted slow progress subscriber")
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+	"transcribe/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	ChannelPrefix     = "job_progress:"
+	UserChannelPrefix = "user_progress:"
+	StreamPrefix      = "job_events:"
+
+	// StreamMaxLen caps the history kept per job. Trimming is approximate, so
+	// a stream may briefly hold a few more entries.
+	StreamMaxLen = 500
+	// StreamTTL is how long a job's history is kept after its last event.
+	StreamTTL = 7 * 24 * time.Hour
+)
+
+// Message is published on the job channel and stored in the job stream.
+// EventID is the stream entry id; it is not part of the stored entry itself.
+type Message struct {
+	EventID   string   `json:"event_id,omitempty"`
+	JobID     string   `json:"job_id"`
+	Status    string   `json:"status"`
+	Progress  *float64 `json:"progress,omitempty"`
+	Timestamp string   `json:"timestamp"`
+}
+
+func Channel(jobID string) string {
+	return ChannelPrefix + jobID
+}
+
+// UserChannel carries the progress of every job a user can view. It is fed
+// by the Relay rather than published to directly.
+func UserChannel(userID uint) string {
+	return UserChannelPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+func Stream(jobID string) string {
+	return StreamPrefix + jobID
+}
+
+func JobIDFromChannel(channel string) string {
+	return strings.TrimPrefix(channel, ChannelPrefix)
+}
+
+// Publish appends a status to the job's stream and then announces it on the
+// job channel, tagged with the new entry id.
+func Publish(ctx context.Context, jobID, status string) error {
+	msg := Message{
+		JobID:     jobID,
+		Status:    status,
+		Timestamp: time.Now().Format(time.RFC3339Nano),
+	}
+
+	payload, err := json.Marshal(msg)
+
+	if err != nil {
+		return err
+	}
+
+	msg.EventID, err = config.RedisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: Stream(jobID),
+		MaxLen: StreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"data": payload},
+	}).Result()
+
+	if err != nil {
+		return err
+	}
+
+	config.RedisClient.Expire(ctx, Stream(jobID), StreamTTL)
+
+	if payload, err = json.Marshal(msg); err != nil {
+		return err
+	}
+
+	return config.RedisClient.Publish(ctx, Channel(jobID), payload).Err()
+}
+
+// Record adds an event to the job's timeline and publishes its stage as
+// progress. One failing does not prevent the other.
+func Record(ctx context.Context, event *domain.JobEvent) error {
+	return errors.Join(
+		repository.NewJobEventRepository().Create(event),
+		Publish(ctx, event.JobID, event.Stage),
+	)
+}
+
+// History returns up to count events of a job after afterID, oldest first.
+// An empty afterID starts from the oldest event still retained.
+func History(ctx context.Context, jobID, afterID string, count int64) ([]Update, error) {
+	start := "-"
+
+	if afterID != "" {
+		start = "(" + afterID
+	}
+
+	entries, err := config.RedisClient.XRangeN(ctx, Stream(jobID), start, "+", count).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]Update, 0, len(entries))
+
+	for _, entry := range entries {
+		data, _ := entry.Values["data"].(string)
+
+		var msg Message
+
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			continue
+		}
+
+		msg.EventID = entry.ID
+
+		payload, err := json.Marshal(msg)
+
+		if err != nil {
+			continue
+		}
+
+		updates = append(updates, Update{Message: msg, Payload: string(payload)})
+	}
+
+	return updates, nil
+}
+
+// ValidEventID reports whether id has the form of a stream entry id.
+func ValidEventID(id string) bool {
+	_, _, ok := parseEventID(id)
+	return ok
+}
+
+// EventAfter reports whether stream entry id a comes after b.
+func EventAfter(a, b string) bool {
+	aMs, aSeq, _ := parseEventID(a)
+	bMs, bSeq, _ := parseEventID(b)
+
+	if aMs != bMs {
+		return aMs > bMs
+	}
+
+	return aSeq > bSeq
+}
+
+func parseEventID(id string) (uint64, uint64, bool) {
+	msPart, seqPart, hasSeq := strings.Cut(id, "-")
+
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+
+	if err != nil {
+		return 0, 0, false
+	}
+
+	if !hasSeq {
+		return ms, 0, true
+	}
+
+	seq, err := strconv.ParseUint(seqPart, 10, 64)
+
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return ms, seq, true
+}
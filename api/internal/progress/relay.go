@@ -0,0 +1,95 @@
+package progress
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/authz"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+)
+
+const relayDedupeTTL = time.Minute
+
+// Relay republishes every job progress message on the user channel of each
+// user who can view the job, so a client can follow all of its jobs over a
+// single subscription.
+type Relay struct {
+	transcriptionRepo *repository.TranscriptionRepository
+	authz             *authz.Service
+}
+
+func NewRelay() *Relay {
+	return &Relay{
+		transcriptionRepo: repository.NewTranscriptionRepository(),
+		authz:             authz.NewService(),
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	pubsub := config.RedisClient.PSubscribe(ctx, ChannelPrefix+"*")
+	defer pubsub.Close()
+
+	logger.Log.Info("progress relay started")
+
+	ch := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Log.Info("progress relay stopped")
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			r.relay(ctx, msg.Channel, msg.Payload)
+		}
+	}
+}
+
+func (r *Relay) relay(ctx context.Context, channel, payload string) {
+	log := logger.Log.WithField("job_id", JobIDFromChannel(channel))
+
+	// Every API instance receives the message; only the first one to claim it
+	// fans it out.
+	sum := sha1.Sum([]byte(channel + payload))
+
+	claimed, err := config.RedisClient.SetNX(ctx, "progress_relay:"+hex.EncodeToString(sum[:]), 1, relayDedupeTTL).Result()
+
+	if err != nil {
+		log.Warnf("failed to claim progress message: %v", err)
+		return
+	}
+
+	if !claimed {
+		return
+	}
+
+	job, err := r.transcriptionRepo.FindByID(JobIDFromChannel(channel))
+
+	if err != nil {
+		log.Warnf("failed to load job for progress relay: %v", err)
+		return
+	}
+
+	viewers, err := r.authz.JobViewers(job)
+
+	if err != nil {
+		log.Errorf("failed to load job viewers: %v", err)
+		return
+	}
+
+	pipe := config.RedisClient.Pipeline()
+
+	for _, userID := range viewers {
+		pipe.Publish(ctx, UserChannel(userID), payload)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Errorf("failed to relay progress message: %v", err)
+	}
+}
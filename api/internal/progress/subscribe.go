@@ -0,0 +1,99 @@
+package progress
+
+import (
+	"context"
+	"sync/atomic"
+	"transcribe/internal/domain"
+)
+
+// Update is a decoded progress message along with the payload it was
+// published as, so it can be forwarded to clients unchanged.
+type Update struct {
+	Message
+	Payload string
+}
+
+// Subscription receives the updates of one channel from a Hub.
+type Subscription struct {
+	hub     *Hub
+	channel string
+	afterID string
+	updates chan Update
+	evicted atomic.Bool
+}
+
+// Follow subscribes to the live progress of a job and reads its history
+// after afterID. The subscription is registered before the history is read
+// and live updates already in the history are skipped, so sending the history
+// and then draining Updates neither misses nor repeats an event.
+func Follow(ctx context.Context, jobID, afterID string) (*Subscription, []Update, error) {
+	sub, err := defaultHub.register(ctx, Channel(jobID), afterID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	history, err := History(ctx, jobID, afterID, StreamMaxLen)
+
+	if err != nil {
+		sub.Close()
+		return nil, nil, err
+	}
+
+	if len(history) > 0 {
+		sub.skipThrough(history[len(history)-1].EventID)
+	}
+
+	return sub, history, nil
+}
+
+// skipThrough drops buffered updates up to and including eventID and makes
+// the hub skip them from now on. It must run before anyone reads Updates.
+func (s *Subscription) skipThrough(eventID string) {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.afterID = eventID
+
+	if s.evicted.Load() {
+		return
+	}
+
+	pending := make([]Update, 0, len(s.updates))
+
+	for len(s.updates) > 0 {
+		pending = append(pending, <-s.updates)
+	}
+
+	for _, update := range pending {
+		if update.EventID == "" || EventAfter(update.EventID, eventID) {
+			s.updates <- update
+		}
+	}
+}
+
+// SubscribeUser listens for progress of all jobs the user can view.
+func SubscribeUser(ctx context.Context, userID uint) (*Subscription, error) {
+	return defaultHub.register(ctx, UserChannel(userID), "")
+}
+
+// Updates is closed when the subscription is closed or the hub evicts it for
+// falling behind.
+func (s *Subscription) Updates() <-chan Update {
+	return s.updates
+}
+
+// Evicted reports whether the hub dropped the subscription because its
+// consumer fell behind.
+func (s *Subscription) Evicted() bool {
+	return s.evicted.Load()
+}
+
+func (s *Subscription) Close() {
+	s.hub.unregister(s)
+}
+
+// IsTerminal reports whether no further progress follows the stage.
+func IsTerminal(stage string) bool {
+	return domain.JobStatus(stage).IsTerminal()
+}
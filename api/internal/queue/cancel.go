@@ -0,0 +1,59 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"transcribe/internal/domain"
+	"transcribe/internal/progress"
+	"transcribe/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Withdraw removes a job that is being cancelled from the queue. It reports
+// true when a payload was removed and no worker holds the job, so nothing is
+// left that could run it.
+func Withdraw(ctx context.Context, q Queue, jobID string) (bool, error) {
+	removed, err := q.Remove(ctx, jobID)
+
+	if err != nil || !removed {
+		return false, err
+	}
+
+	leased, err := q.Leased(ctx, jobID)
+
+	if err != nil {
+		return false, err
+	}
+
+	return !leased, nil
+}
+
+// CompleteCancel moves a cancelling job to cancelled and records why. It
+// reports false when the job was no longer cancelling, for example because
+// the worker acknowledged the cancellation first.
+func CompleteCancel(ctx context.Context, transcriptionRepo *repository.TranscriptionRepository, jobID, msg string, log *logrus.Entry) bool {
+	err := transcriptionRepo.Transition(jobID, domain.JobStatusCancelled, nil)
+
+	if errors.Is(err, domain.ErrIllegalTransition) {
+		return false
+	}
+
+	if err != nil {
+		log.Errorf("failed to mark job as cancelled: %v", err)
+		return false
+	}
+
+	if err := progress.Record(ctx, &domain.JobEvent{
+		JobID:   jobID,
+		Stage:   string(domain.JobStatusCancelled),
+		Message: msg,
+		Actor:   domain.JobEventActorSystem,
+	}); err != nil {
+		log.Warnf("failed to record cancellation event: %v", err)
+	}
+
+	log.Warn(msg)
+
+	return true
+}
@@ -0,0 +1,98 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+	"transcribe/internal/domain"
+)
+
+const (
+	PendingKey    = "transcription_queue"
+	LeasesKey     = "transcription_queue:leases"
+	WorkersKey    = "transcription_queue:workers"
+	DelayedKey    = "transcription_queue:delayed"
+	DeadKey       = "transcription_queue:dead"
+	processingKey = "transcription_queue:processing:"
+	workerKey     = "transcription_queue:worker:"
+)
+
+var (
+	ErrEmpty       = errors.New("queue is empty")
+	ErrNotClaimed  = errors.New("job is no longer claimed")
+	ErrDeadMissing = errors.New("dead job not found")
+)
+
+type Job struct {
+	JobID       string                      `json:"job_id"`
+	StorageKey  string                      `json:"storage_key"`
+	UserID      uint                        `json:"user_id"`
+	Options     domain.TranscriptionOptions `json:"options"`
+	Attempts    int                         `json:"attempts"`
+	MaxAttempts int                         `json:"max_attempts"`
+
+	raw      string
+	workerID string
+}
+
+type DeadJob struct {
+	Job      Job       `json:"job"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failed_at"`
+
+	raw string
+}
+
+type Stats struct {
+	Pending    int64 `json:"pending"`
+	Delayed    int64 `json:"delayed"`
+	Processing int64 `json:"processing"`
+	Dead       int64 `json:"dead"`
+	Workers    int64 `json:"workers"`
+}
+
+type Queue interface {
+	Enqueue(ctx context.Context, job *Job) error
+	Claim(ctx context.Context, workerID string, timeout time.Duration) (*Job, error)
+	Heartbeat(ctx context.Context, job *Job) error
+	Ack(ctx context.Context, job *Job) error
+	Leased(ctx context.Context, jobID string) (bool, error)
+	Remove(ctx context.Context, jobID string) (bool, error)
+	Expired(ctx context.Context) ([]*Job, error)
+	Requeue(ctx context.Context, job *Job, delay time.Duration) error
+	DeadLetter(ctx context.Context, job *Job, reason string) error
+	Drop(ctx context.Context, job *Job) error
+	PromoteDue(ctx context.Context, limit int) ([]*Job, error)
+	DeadJobs(ctx context.Context, offset, limit int64) ([]DeadJob, int64, error)
+	FindDead(ctx context.Context, jobID string) (*DeadJob, error)
+	ReplayDead(ctx context.Context, jobID string) (*Job, error)
+	DiscardDead(ctx context.Context, jobID string) error
+	Depth(ctx context.Context) (int64, error)
+	Stats(ctx context.Context) (*Stats, error)
+}
+
+func ProcessingKey(workerID string) string {
+	return processingKey + workerID
+}
+
+func WorkerKey(workerID string) string {
+	return workerKey + workerID
+}
+
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	delay := base
+
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+
+		if delay >= max {
+			return max
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,205 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+	"transcribe/internal/progress"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+const cancelBatchSize = 100
+
+type Reaper struct {
+	queue             Queue
+	transcriptionRepo *repository.TranscriptionRepository
+	interval          time.Duration
+	maxAttempts       int
+	retryBaseDelay    time.Duration
+	retryMaxDelay     time.Duration
+	cancelTimeout     time.Duration
+}
+
+func NewReaper(q Queue) *Reaper {
+	return &Reaper{
+		queue:             q,
+		transcriptionRepo: repository.NewTranscriptionRepository(),
+		interval:          config.AppConfig.QueueReaperInterval,
+		maxAttempts:       config.AppConfig.QueueMaxAttempts,
+		retryBaseDelay:    config.AppConfig.QueueRetryBaseDelay,
+		retryMaxDelay:     config.AppConfig.QueueRetryMaxDelay,
+		cancelTimeout:     config.AppConfig.QueueCancelTimeout,
+	}
+}
+
+func (r *Reaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	logger.Log.Infof("queue reaper started (interval %s, max attempts %d)", r.interval, r.maxAttempts)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Log.Info("queue reaper stopped")
+			return
+		case <-ticker.C:
+			r.sweep(ctx)
+		}
+	}
+}
+
+func (r *Reaper) sweep(ctx context.Context) {
+	r.reapLeases(ctx)
+	r.expireCancellations(ctx)
+}
+
+func (r *Reaper) reapLeases(ctx context.Context) {
+	jobs, err := r.queue.Expired(ctx)
+
+	if err != nil {
+		logger.Log.Errorf("failed to scan expired queue leases: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		log := logger.Log.WithFields(logrus.Fields{
+			"job_id":   job.JobID,
+			"user_id":  job.UserID,
+			"worker":   job.workerID,
+			"attempts": job.Attempts + 1,
+		})
+
+		if current, err := r.transcriptionRepo.FindByID(job.JobID); err == nil {
+			if current.Status.IsTerminal() {
+				if err := r.queue.Drop(ctx, job); err != nil {
+					log.Errorf("failed to drop finished job: %v", err)
+				}
+				continue
+			}
+
+			if current.Status == domain.JobStatusCancelling {
+				if err := r.queue.Drop(ctx, job); err != nil {
+					log.Errorf("failed to drop cancelled job: %v", err)
+					continue
+				}
+
+				CompleteCancel(ctx, r.transcriptionRepo, job.JobID, "worker lease expired before the cancellation was acknowledged", log)
+				continue
+			}
+		}
+
+		maxAttempts := job.MaxAttempts
+		if maxAttempts < 1 {
+			maxAttempts = r.maxAttempts
+		}
+
+		if job.Attempts+1 >= maxAttempts {
+			r.deadLetter(ctx, job, log)
+			continue
+		}
+
+		delay := Backoff(job.Attempts+1, r.retryBaseDelay, r.retryMaxDelay)
+
+		if err := r.queue.Requeue(ctx, job, delay); err != nil {
+			log.Warnf("failed to schedule retry for expired job: %v", err)
+			continue
+		}
+
+		nextRetryAt := time.Now().Add(delay)
+
+		err := r.transcriptionRepo.Transition(job.JobID, domain.JobStatusRetrying, map[string]interface{}{
+			"attempts":      job.Attempts,
+			"next_retry_at": nextRetryAt,
+			"error_msg":     "worker lease expired",
+		})
+
+		if err != nil {
+			log.Errorf("failed to record retry for expired job: %v", err)
+		} else if err := progress.Record(ctx, &domain.JobEvent{
+			JobID:   job.JobID,
+			Stage:   string(domain.JobStatusRetrying),
+			Message: fmt.Sprintf("worker lease expired, retrying in %s", delay),
+			Actor:   domain.JobEventActorSystem,
+		}); err != nil {
+			log.Warnf("failed to record retry event: %v", err)
+		}
+
+		log.Warnf("job lease expired, retrying in %s", delay)
+	}
+}
+
+func (r *Reaper) deadLetter(ctx context.Context, job *Job, log *logrus.Entry) {
+	msg := fmt.Sprintf("worker did not finish the job after %d attempts", job.Attempts+1)
+
+	if err := r.queue.DeadLetter(ctx, job, msg); err != nil {
+		log.Errorf("failed to dead-letter expired job: %v", err)
+		return
+	}
+
+	// The attempt count is written with the status; the dead-job listing and
+	// replay rely on it.
+	if err := r.transcriptionRepo.Transition(job.JobID, domain.JobStatusFailed, map[string]interface{}{
+		"attempts":      job.Attempts,
+		"error_msg":     msg,
+		"next_retry_at": nil,
+		"completed_at":  time.Now(),
+	}); err != nil {
+		log.Errorf("failed to mark expired job as failed: %v", err)
+		return
+	}
+
+	if err := progress.Record(ctx, &domain.JobEvent{
+		JobID:   job.JobID,
+		Stage:   string(domain.JobStatusFailed),
+		Message: msg,
+		Actor:   domain.JobEventActorSystem,
+	}); err != nil {
+		log.Warnf("failed to record failure event: %v", err)
+	}
+
+	log.Error("job lease expired too many times, moved to dead-letter queue")
+}
+
+// expireCancellations cancels jobs that have waited in cancelling longer than
+// the timeout with no worker holding them, for example because a worker
+// released the job without acknowledging the cancellation.
+func (r *Reaper) expireCancellations(ctx context.Context) {
+	jobs, err := r.transcriptionRepo.FindStale(domain.JobStatusCancelling, time.Now().Add(-r.cancelTimeout), cancelBatchSize)
+
+	if err != nil {
+		logger.Log.Errorf("failed to scan pending cancellations: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		log := logger.Log.WithFields(logrus.Fields{
+			"job_id":  job.ID,
+			"user_id": job.UserID,
+		})
+
+		leased, err := r.queue.Leased(ctx, job.ID)
+
+		if err != nil {
+			log.Errorf("failed to check job lease: %v", err)
+			continue
+		}
+
+		if leased {
+			continue
+		}
+
+		// A payload can reach the queue after the cancellation, e.g. from the
+		// outbox; take it out before giving up on the job.
+		if _, err := r.queue.Remove(ctx, job.ID); err != nil {
+			log.Warnf("failed to remove cancelled job from the queue: %v", err)
+		}
+
+		CompleteCancel(ctx, r.transcriptionRepo, job.ID, fmt.Sprintf("no worker acknowledged the cancellation within %s", r.cancelTimeout), log)
+	}
+}
@@ -0,0 +1,436 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"transcribe/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var requeueScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+	return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[2])
+redis.call('ZADD', KEYS[3], ARGV[4], ARGV[3])
+return 1
+`)
+
+var deadLetterScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+	return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[2])
+redis.call('LPUSH', KEYS[3], ARGV[3])
+return 1
+`)
+
+var dropScript = redis.NewScript(`
+local removed = redis.call('LREM', KEYS[1], 1, ARGV[1])
+redis.call('ZREM', KEYS[2], ARGV[2])
+return removed
+`)
+
+var promoteScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, payload in ipairs(due) do
+	redis.call('ZREM', KEYS[1], payload)
+	redis.call('RPUSH', KEYS[2], payload)
+end
+return due
+`)
+
+var replayDeadScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+	return 0
+end
+redis.call('RPUSH', KEYS[2], ARGV[2])
+return 1
+`)
+
+var removeScript = redis.NewScript(`
+local removed = 0
+for _, payload in ipairs(redis.call('LRANGE', KEYS[1], 0, -1)) do
+	local ok, job = pcall(cjson.decode, payload)
+	if ok and job['job_id'] == ARGV[1] then
+		removed = removed + redis.call('LREM', KEYS[1], 0, payload)
+	end
+end
+for _, payload in ipairs(redis.call('ZRANGE', KEYS[2], 0, -1)) do
+	local ok, job = pcall(cjson.decode, payload)
+	if ok and job['job_id'] == ARGV[1] then
+		removed = removed + redis.call('ZREM', KEYS[2], payload)
+	end
+end
+return removed
+`)
+
+type RedisQueue struct {
+	client            *redis.Client
+	visibilityTimeout time.Duration
+}
+
+func NewRedisQueue() *RedisQueue {
+	return &RedisQueue{
+		client:            config.RedisClient,
+		visibilityTimeout: config.AppConfig.QueueVisibilityTimeout,
+	}
+}
+
+func (q *RedisQueue) Enqueue(ctx context.Context, job *Job) error {
+	payload, err := json.Marshal(job)
+
+	if err != nil {
+		return err
+	}
+
+	return q.client.RPush(ctx, PendingKey, payload).Err()
+}
+
+func (q *RedisQueue) Claim(ctx context.Context, workerID string, timeout time.Duration) (*Job, error) {
+	pipe := q.client.TxPipeline()
+	pipe.SAdd(ctx, WorkersKey, workerID)
+	pipe.Set(ctx, WorkerKey(workerID), time.Now().Unix(), q.visibilityTimeout)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	raw, err := q.client.BLMove(ctx, PendingKey, ProcessingKey(workerID), "LEFT", "RIGHT", timeout).Result()
+
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrEmpty
+		}
+		return nil, err
+	}
+
+	job, err := decodeJob(raw, workerID)
+
+	if err != nil {
+		q.client.LRem(ctx, ProcessingKey(workerID), 1, raw)
+		return nil, err
+	}
+
+	if err := q.Heartbeat(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (q *RedisQueue) Heartbeat(ctx context.Context, job *Job) error {
+	pipe := q.client.TxPipeline()
+	pipe.ZAdd(ctx, LeasesKey, redis.Z{
+		Score:  float64(time.Now().Add(q.visibilityTimeout).Unix()),
+		Member: job.JobID,
+	})
+	pipe.Set(ctx, WorkerKey(job.workerID), time.Now().Unix(), q.visibilityTimeout)
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (q *RedisQueue) Ack(ctx context.Context, job *Job) error {
+	return q.Drop(ctx, job)
+}
+
+// Leased reports whether a worker holds the job, including a worker whose
+// lease has expired but has not been reaped yet.
+func (q *RedisQueue) Leased(ctx context.Context, jobID string) (bool, error) {
+	err := q.client.ZScore(ctx, LeasesKey, jobID).Err()
+
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Remove takes every payload of the job out of the pending list and the
+// delayed set. Jobs claimed by a worker are not touched.
+func (q *RedisQueue) Remove(ctx context.Context, jobID string) (bool, error) {
+	removed, err := removeScript.Run(ctx, q.client, []string{PendingKey, DelayedKey}, jobID).Int()
+
+	return removed > 0, err
+}
+
+func (q *RedisQueue) Expired(ctx context.Context) ([]*Job, error) {
+	workers, err := q.client.SMembers(ctx, WorkersKey).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var expired []*Job
+
+	for _, workerID := range workers {
+		items, err := q.client.LRange(ctx, ProcessingKey(workerID), 0, -1).Result()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(items) == 0 {
+			q.forgetIdleWorker(ctx, workerID)
+			continue
+		}
+
+		for _, raw := range items {
+			job, err := decodeJob(raw, workerID)
+
+			if err != nil {
+				q.client.LRem(ctx, ProcessingKey(workerID), 1, raw)
+				continue
+			}
+
+			deadline, err := q.client.ZScore(ctx, LeasesKey, job.JobID).Result()
+
+			if errors.Is(err, redis.Nil) {
+				q.client.ZAddNX(ctx, LeasesKey, redis.Z{
+					Score:  float64(now.Add(q.visibilityTimeout).Unix()),
+					Member: job.JobID,
+				})
+				continue
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			if int64(deadline) < now.Unix() {
+				expired = append(expired, job)
+			}
+		}
+	}
+
+	return expired, nil
+}
+
+func (q *RedisQueue) Requeue(ctx context.Context, job *Job, delay time.Duration) error {
+	retry := *job
+	retry.Attempts++
+
+	payload, err := json.Marshal(&retry)
+
+	if err != nil {
+		return err
+	}
+
+	moved, err := requeueScript.Run(ctx, q.client,
+		[]string{ProcessingKey(job.workerID), LeasesKey, DelayedKey},
+		job.raw, job.JobID, payload, time.Now().Add(delay).Unix(),
+	).Int()
+
+	if err != nil {
+		return err
+	}
+
+	if moved == 0 {
+		return ErrNotClaimed
+	}
+
+	job.Attempts = retry.Attempts
+
+	return nil
+}
+
+func (q *RedisQueue) DeadLetter(ctx context.Context, job *Job, reason string) error {
+	dead := *job
+	dead.Attempts++
+
+	payload, err := json.Marshal(DeadJob{
+		Job:      dead,
+		Error:    reason,
+		FailedAt: time.Now(),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	moved, err := deadLetterScript.Run(ctx, q.client,
+		[]string{ProcessingKey(job.workerID), LeasesKey, DeadKey},
+		job.raw, job.JobID, payload,
+	).Int()
+
+	if err != nil {
+		return err
+	}
+
+	if moved == 0 {
+		return ErrNotClaimed
+	}
+
+	job.Attempts = dead.Attempts
+
+	return nil
+}
+
+func (q *RedisQueue) Drop(ctx context.Context, job *Job) error {
+	return dropScript.Run(ctx, q.client,
+		[]string{ProcessingKey(job.workerID), LeasesKey},
+		job.raw, job.JobID,
+	).Err()
+}
+
+func (q *RedisQueue) PromoteDue(ctx context.Context, limit int) ([]*Job, error) {
+	payloads, err := promoteScript.Run(ctx, q.client,
+		[]string{DelayedKey, PendingKey},
+		time.Now().Unix(), limit,
+	).StringSlice()
+
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(payloads))
+
+	for _, raw := range payloads {
+		if job, err := decodeJob(raw, ""); err == nil {
+			jobs = append(jobs, job)
+		}
+	}
+
+	return jobs, nil
+}
+
+func (q *RedisQueue) DeadJobs(ctx context.Context, offset, limit int64) ([]DeadJob, int64, error) {
+	total, err := q.client.LLen(ctx, DeadKey).Result()
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, err := q.client.LRange(ctx, DeadKey, offset, offset+limit-1).Result()
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	jobs := make([]DeadJob, 0, len(items))
+
+	for _, raw := range items {
+		var dead DeadJob
+		if err := json.Unmarshal([]byte(raw), &dead); err == nil {
+			jobs = append(jobs, dead)
+		}
+	}
+
+	return jobs, total, nil
+}
+
+func (q *RedisQueue) ReplayDead(ctx context.Context, jobID string) (*Job, error) {
+	dead, err := q.FindDead(ctx, jobID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	job := dead.Job
+	job.Attempts = 0
+
+	payload, err := json.Marshal(&job)
+
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := replayDeadScript.Run(ctx, q.client,
+		[]string{DeadKey, PendingKey},
+		dead.raw, payload,
+	).Int()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if moved == 0 {
+		return nil, ErrDeadMissing
+	}
+
+	return &job, nil
+}
+
+func (q *RedisQueue) DiscardDead(ctx context.Context, jobID string) error {
+	dead, err := q.FindDead(ctx, jobID)
+
+	if err != nil {
+		return err
+	}
+
+	return q.client.LRem(ctx, DeadKey, 1, dead.raw).Err()
+}
+
+func (q *RedisQueue) Depth(ctx context.Context) (int64, error) {
+	return q.client.LLen(ctx, PendingKey).Result()
+}
+
+func (q *RedisQueue) Stats(ctx context.Context) (*Stats, error) {
+	pipe := q.client.Pipeline()
+
+	pending := pipe.LLen(ctx, PendingKey)
+	delayed := pipe.ZCard(ctx, DelayedKey)
+	processing := pipe.ZCard(ctx, LeasesKey)
+	dead := pipe.LLen(ctx, DeadKey)
+	workers := pipe.SCard(ctx, WorkersKey)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return &Stats{
+		Pending:    pending.Val(),
+		Delayed:    delayed.Val(),
+		Processing: processing.Val(),
+		Dead:       dead.Val(),
+		Workers:    workers.Val(),
+	}, nil
+}
+
+func (q *RedisQueue) forgetIdleWorker(ctx context.Context, workerID string) {
+	alive, err := q.client.Exists(ctx, WorkerKey(workerID)).Result()
+
+	if err == nil && alive == 0 {
+		q.client.SRem(ctx, WorkersKey, workerID)
+	}
+}
+
+func (q *RedisQueue) FindDead(ctx context.Context, jobID string) (*DeadJob, error) {
+	items, err := q.client.LRange(ctx, DeadKey, 0, -1).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, raw := range items {
+		var dead DeadJob
+		if err := json.Unmarshal([]byte(raw), &dead); err != nil {
+			continue
+		}
+
+		if dead.Job.JobID == jobID {
+			dead.raw = raw
+			return &dead, nil
+		}
+	}
+
+	return nil, ErrDeadMissing
+}
+
+func decodeJob(raw, workerID string) (*Job, error) {
+	var job Job
+
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		return nil, err
+	}
+
+	job.raw = raw
+	job.workerID = workerID
+
+	return &job, nil
+}
@@ -0,0 +1,90 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+	"transcribe/internal/progress"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+)
+
+const promoteBatchSize = 100
+
+type Scheduler struct {
+	queue             Queue
+	transcriptionRepo *repository.TranscriptionRepository
+	interval          time.Duration
+}
+
+func NewScheduler(q Queue) *Scheduler {
+	return &Scheduler{
+		queue:             q,
+		transcriptionRepo: repository.NewTranscriptionRepository(),
+		interval:          config.AppConfig.QueueSchedulerInterval,
+	}
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	logger.Log.Infof("retry scheduler started (interval %s)", s.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Log.Info("retry scheduler stopped")
+			return
+		case <-ticker.C:
+			s.promote(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) promote(ctx context.Context) {
+	for {
+		jobs, err := s.queue.PromoteDue(ctx, promoteBatchSize)
+
+		if err != nil {
+			logger.Log.Errorf("failed to promote delayed jobs: %v", err)
+			return
+		}
+
+		for _, job := range jobs {
+			log := logger.Log.WithField("job_id", job.JobID)
+
+			err := s.transcriptionRepo.Transition(job.JobID, domain.JobStatusQueued, map[string]interface{}{
+				"next_retry_at": nil,
+			})
+
+			if errors.Is(err, domain.ErrIllegalTransition) {
+				// Cancelled or picked up while it waited.
+				continue
+			}
+
+			if err != nil {
+				log.Errorf("failed to mark retried job as queued: %v", err)
+				continue
+			}
+
+			if err := progress.Record(ctx, &domain.JobEvent{
+				JobID:   job.JobID,
+				Stage:   string(domain.JobStatusQueued),
+				Message: fmt.Sprintf("retry attempt %d queued", job.Attempts+1),
+				Actor:   domain.JobEventActorSystem,
+			}); err != nil {
+				log.Warnf("failed to record retry event: %v", err)
+			}
+
+			log.Infof("retry attempt %d queued", job.Attempts+1)
+		}
+
+		if len(jobs) < promoteBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"errors"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository struct{}
+
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{}
+}
+
+func (r *APIKeyRepository) Create(key *domain.APIKey) error {
+	return config.DB.Create(key).Error
+}
+
+func (r *APIKeyRepository) FindByID(keyID uint) (*domain.APIKey, error) {
+	var key domain.APIKey
+
+	result := config.DB.Where("id = ?", keyID).First(&key)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("api key not found")
+		}
+		return nil, result.Error
+	}
+
+	return &key, nil
+}
+
+func (r *APIKeyRepository) FindByHash(hash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+
+	result := config.DB.Where("key_hash = ?", hash).First(&key)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("api key not found")
+		}
+		return nil, result.Error
+	}
+
+	return &key, nil
+}
+
+func (r *APIKeyRepository) FindByUserID(userID uint) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+
+	result := config.DB.Where("user_id = ?", userID).Order("id DESC").Find(&keys)
+
+	return keys, result.Error
+}
+
+func (r *APIKeyRepository) Revoke(keyID uint) error {
+	return config.DB.Model(&domain.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", keyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *APIKeyRepository) TouchLastUsed(keyID uint, usedAt time.Time) error {
+	return config.DB.Model(&domain.APIKey{}).
+		Where("id = ?", keyID).
+		UpdateColumn("last_used_at", usedAt).Error
+}
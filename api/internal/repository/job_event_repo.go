@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"transcribe/config"
+	"transcribe/internal/domain"
+)
+
+type JobEventRepository struct{}
+
+func NewJobEventRepository() *JobEventRepository {
+	return &JobEventRepository{}
+}
+
+func (r *JobEventRepository) Create(event *domain.JobEvent) error {
+	return config.DB.Create(event).Error
+}
+
+func (r *JobEventRepository) FindByJobID(jobID string) ([]domain.JobEvent, error) {
+	var events []domain.JobEvent
+
+	result := config.DB.Where("job_id = ?", jobID).Order("created_at ASC, id ASC").Find(&events)
+
+	return events, result.Error
+}
+
+func (r *JobEventRepository) DeleteByJobID(jobID string) error {
+	return config.DB.Where("job_id = ?", jobID).Delete(&domain.JobEvent{}).Error
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository struct{}
+
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{}
+}
+
+func (r *OutboxRepository) ProcessPending(limit int, handle func(msg *domain.OutboxMessage) error) (int, error) {
+	processed := 0
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var messages []domain.OutboxMessage
+
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND available_at <= ?", domain.OutboxStatusPending, time.Now()).
+			Order("id ASC").
+			Limit(limit).
+			Find(&messages)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		for i := range messages {
+			msg := &messages[i]
+
+			if err := handle(msg); err != nil {
+				updates := map[string]interface{}{
+					"attempts":     gorm.Expr("attempts + 1"),
+					"last_error":   err.Error(),
+					"available_at": time.Now().Add(outboxBackoff(msg.Attempts + 1)),
+				}
+
+				if err := tx.Model(msg).Updates(updates).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			now := time.Now()
+			updates := map[string]interface{}{
+				"status":  domain.OutboxStatusSent,
+				"sent_at": now,
+			}
+
+			if err := tx.Model(msg).Updates(updates).Error; err != nil {
+				return err
+			}
+
+			processed++
+		}
+
+		return nil
+	})
+
+	return processed, err
+}
+
+func (r *OutboxRepository) DeleteSentBefore(before time.Time) (int64, error) {
+	result := config.DB.Where("status = ? AND sent_at < ?", domain.OutboxStatusSent, before).Delete(&domain.OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
+
+func outboxBackoff(attempts int) time.Duration {
+	delay := time.Second
+
+	for i := 1; i < attempts && delay < time.Minute; i++ {
+		delay *= 2
+	}
+
+	if delay > time.Minute {
+		return time.Minute
+	}
+
+	return delay
+}
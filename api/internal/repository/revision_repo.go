@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+	"transcribe/config"
+	"transcribe/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+var ErrRevisionConflict = errors.New("transcript was changed by someone else")
+
+type RevisionRepository struct{}
+
+func NewRevisionRepository() *RevisionRepository {
+	return &RevisionRepository{}
+}
+
+// Save stores rev as the job's next revision and makes it the current
+// transcript, provided the job is still at job.Revision. The worker output is
+// kept as revision 0 the first time a transcript is edited. Clearing
+// indexed_at makes the search indexer pick up the new segments.
+func (r *RevisionRepository) Save(job *domain.TranscriptionJob, rev *domain.TranscriptRevision) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if job.Revision == 0 {
+			original := &domain.TranscriptRevision{
+				JobID:    job.ID,
+				Number:   0,
+				Action:   domain.RevisionActionOriginal,
+				Segments: job.Segments,
+				Text:     job.Text,
+			}
+
+			if err := tx.Create(original).Error; err != nil {
+				if errors.Is(err, gorm.ErrDuplicatedKey) {
+					return ErrRevisionConflict
+				}
+				return err
+			}
+		}
+
+		result := tx.Model(&domain.TranscriptionJob{}).
+			Where("id = ? AND revision = ?", job.ID, job.Revision).
+			Updates(map[string]interface{}{
+				"segments":   rev.Segments,
+				"text":       rev.Text,
+				"revision":   job.Revision + 1,
+				"indexed_at": nil,
+			})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrRevisionConflict
+		}
+
+		rev.JobID = job.ID
+		rev.Number = job.Revision + 1
+
+		return tx.Create(rev).Error
+	})
+}
+
+func (r *RevisionRepository) FindByJobID(jobID string, page, pageSize int) ([]domain.TranscriptRevision, int64, error) {
+	var revisions []domain.TranscriptRevision
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	query := config.DB.Model(&domain.TranscriptRevision{}).Where("job_id = ?", jobID)
+
+	query = query.Session(&gorm.Session{})
+	query.Count(&total)
+
+	result := query.Omit("segments", "text").
+		Order("number DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&revisions)
+
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return revisions, total, nil
+}
+
+func (r *RevisionRepository) FindByNumber(jobID string, number int) (*domain.TranscriptRevision, error) {
+	var revision domain.TranscriptRevision
+
+	result := config.DB.Where("job_id = ? AND number = ?", jobID, number).First(&revision)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("revision not found")
+		}
+		return nil, result.Error
+	}
+
+	return &revision, nil
+}
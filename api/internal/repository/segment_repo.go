@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"errors"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ErrIndexStale is returned when the transcript changed while it was being
+// indexed. The job stays unindexed and is picked up again.
+var ErrIndexStale = errors.New("transcript changed while it was indexed")
+
+type SegmentRepository struct{}
+
+type SegmentMatch struct {
+	domain.TranscriptSegment
+	Score float64
+}
+
+type JobMatch struct {
+	JobID string
+	Score float64
+}
+
+func NewSegmentRepository() *SegmentRepository {
+	return &SegmentRepository{}
+}
+
+// ReplaceForJob stores segments built from the given revision of the job's
+// transcript. It only marks the job indexed if the job is still at that
+// revision; otherwise nothing is written.
+func (r *SegmentRepository) ReplaceForJob(jobID string, revision int, segments []domain.TranscriptSegment) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("job_id = ?", jobID).Delete(&domain.TranscriptSegment{}).Error; err != nil {
+			return err
+		}
+
+		if len(segments) > 0 {
+			if err := tx.CreateInBatches(segments, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&domain.TranscriptionJob{}).
+			Where("id = ? AND revision = ?", jobID, revision).
+			Update("indexed_at", time.Now())
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrIndexStale
+		}
+
+		return nil
+	})
+}
+
+func (r *SegmentRepository) DeleteByJobID(jobID string) error {
+	return config.DB.Where("job_id = ?", jobID).Delete(&domain.TranscriptSegment{}).Error
+}
+
+func (r *SegmentRepository) FindUnindexedJobs(limit int) ([]domain.TranscriptionJob, error) {
+	var jobs []domain.TranscriptionJob
+
+	result := config.DB.Where("status = ? AND indexed_at IS NULL", domain.JobStatusDone).
+		Order("completed_at ASC").
+		Limit(limit).
+		Find(&jobs)
+
+	return jobs, result.Error
+}
+
+func (r *SegmentRepository) SearchJobs(scope domain.JobScope, booleanQuery string, speakers []string, page, pageSize int) ([]JobMatch, int64, error) {
+	var total int64
+	var matches []JobMatch
+
+	offset := (page - 1) * pageSize
+
+	err := r.matchQuery(scope, booleanQuery, speakers).
+		Distinct("job_id").
+		Count(&total).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := r.matchQuery(scope, booleanQuery, speakers).
+		Select("job_id, MAX(MATCH(text) AGAINST (? IN BOOLEAN MODE)) AS score", booleanQuery).
+		Group("job_id").
+		Order("score DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Scan(&matches)
+
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return matches, total, nil
+}
+
+func (r *SegmentRepository) SearchSegments(scope domain.JobScope, jobIDs []string, booleanQuery string, speakers []string) ([]SegmentMatch, error) {
+	var matches []SegmentMatch
+
+	result := r.matchQuery(scope, booleanQuery, speakers).
+		Select("*, MATCH(text) AGAINST (? IN BOOLEAN MODE) AS score", booleanQuery).
+		Where("job_id IN ?", jobIDs).
+		Order("job_id, start_time").
+		Scan(&matches)
+
+	return matches, result.Error
+}
+
+func (r *SegmentRepository) matchQuery(scope domain.JobScope, booleanQuery string, speakers []string) *gorm.DB {
+	jobs := config.DB.Model(&domain.TranscriptionJob{}).
+		Select("id").
+		Where(scopeCondition(scope, config.DB))
+
+	query := config.DB.Model(&domain.TranscriptSegment{}).
+		Where("job_id IN (?)", jobs).
+		Where("MATCH(text) AGAINST (? IN BOOLEAN MODE)", booleanQuery)
+
+	// A speaker matches by raw label or by the name it was given in the job.
+	if len(speakers) > 0 {
+		named := config.DB.Model(&domain.JobSpeaker{}).
+			Select("1").
+			Where("job_speakers.job_id = transcript_segments.job_id AND job_speakers.label = transcript_segments.speaker").
+			Where("job_speakers.name IN ?", speakers)
+
+		query = query.Where(config.DB.Where("transcript_segments.speaker IN ?", speakers).Or("EXISTS (?)", named))
+	}
+
+	return query
+}
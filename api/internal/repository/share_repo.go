@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"errors"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type ShareRepository struct{}
+
+func NewShareRepository() *ShareRepository {
+	return &ShareRepository{}
+}
+
+func (r *ShareRepository) Create(link *domain.ShareLink) error {
+	return config.DB.Create(link).Error
+}
+
+func (r *ShareRepository) FindByID(linkID uint) (*domain.ShareLink, error) {
+	var link domain.ShareLink
+
+	result := config.DB.Where("id = ?", linkID).First(&link)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("share link not found")
+		}
+		return nil, result.Error
+	}
+
+	return &link, nil
+}
+
+func (r *ShareRepository) FindByHash(hash string) (*domain.ShareLink, error) {
+	var link domain.ShareLink
+
+	result := config.DB.Where("token_hash = ?", hash).First(&link)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("share link not found")
+		}
+		return nil, result.Error
+	}
+
+	return &link, nil
+}
+
+func (r *ShareRepository) FindByJobID(jobID string) ([]domain.ShareLink, error) {
+	var links []domain.ShareLink
+
+	result := config.DB.Where("job_id = ?", jobID).Order("id DESC").Find(&links)
+
+	return links, result.Error
+}
+
+func (r *ShareRepository) Revoke(linkID uint) error {
+	return config.DB.Model(&domain.ShareLink{}).
+		Where("id = ? AND revoked_at IS NULL", linkID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *ShareRepository) RecordAccess(linkID uint, accessedAt time.Time) error {
+	return config.DB.Model(&domain.ShareLink{}).
+		Where("id = ?", linkID).
+		UpdateColumns(map[string]interface{}{
+			"access_count":     gorm.Expr("access_count + 1"),
+			"last_accessed_at": accessedAt,
+		}).Error
+}
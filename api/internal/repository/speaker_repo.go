@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"errors"
+	"transcribe/config"
+	"transcribe/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSpeakerProfileNotFound = errors.New("speaker profile not found")
+	ErrSpeakerProfileExists   = errors.New("a speaker profile with this name already exists")
+)
+
+type SpeakerRepository struct{}
+
+func NewSpeakerRepository() *SpeakerRepository {
+	return &SpeakerRepository{}
+}
+
+func (r *SpeakerRepository) CreateProfile(profile *domain.SpeakerProfile) error {
+	result := config.DB.Create(profile)
+
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return ErrSpeakerProfileExists
+	}
+
+	return result.Error
+}
+
+func (r *SpeakerRepository) FindProfile(profileID uint) (*domain.SpeakerProfile, error) {
+	var profile domain.SpeakerProfile
+
+	result := config.DB.First(&profile, profileID)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrSpeakerProfileNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &profile, nil
+}
+
+func (r *SpeakerRepository) FindProfilesByUserID(userID uint) ([]domain.SpeakerProfile, error) {
+	var profiles []domain.SpeakerProfile
+
+	result := config.DB.Where("user_id = ?", userID).Order("name ASC").Find(&profiles)
+
+	return profiles, result.Error
+}
+
+func (r *SpeakerRepository) FindProfilesByIDs(profileIDs []uint) ([]domain.SpeakerProfile, error) {
+	var profiles []domain.SpeakerProfile
+
+	result := config.DB.Where("id IN ?", profileIDs).Find(&profiles)
+
+	return profiles, result.Error
+}
+
+// UpdateProfile also renames every job speaker linked to the profile.
+func (r *SpeakerRepository) UpdateProfile(profile *domain.SpeakerProfile) error {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(profile).Updates(map[string]interface{}{
+			"name":  profile.Name,
+			"notes": profile.Notes,
+		}).Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.JobSpeaker{}).
+			Where("profile_id = ?", profile.ID).
+			Update("name", profile.Name).Error
+	})
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrSpeakerProfileExists
+	}
+
+	return err
+}
+
+// DeleteProfile unlinks the profile from jobs, which keep its last name.
+func (r *SpeakerRepository) DeleteProfile(profileID uint) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.JobSpeaker{}).
+			Where("profile_id = ?", profileID).
+			Update("profile_id", nil).Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&domain.SpeakerProfile{}, profileID).Error
+	})
+}
+
+func (r *SpeakerRepository) FindByJobID(jobID string) ([]domain.JobSpeaker, error) {
+	var speakers []domain.JobSpeaker
+
+	result := config.DB.Where("job_id = ?", jobID).Order("label ASC").Find(&speakers)
+
+	return speakers, result.Error
+}
+
+// NamesForJobs returns the speaker map of each job, keyed by job id and label.
+func (r *SpeakerRepository) NamesForJobs(jobIDs []string) (map[string]map[string]string, error) {
+	var speakers []domain.JobSpeaker
+
+	names := make(map[string]map[string]string)
+
+	if len(jobIDs) == 0 {
+		return names, nil
+	}
+
+	if err := config.DB.Where("job_id IN ?", jobIDs).Find(&speakers).Error; err != nil {
+		return nil, err
+	}
+
+	for _, speaker := range speakers {
+		if names[speaker.JobID] == nil {
+			names[speaker.JobID] = make(map[string]string)
+		}
+
+		names[speaker.JobID][speaker.Label] = speaker.Name
+	}
+
+	return names, nil
+}
+
+func (r *SpeakerRepository) ReplaceForJob(jobID string, speakers []domain.JobSpeaker) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("job_id = ?", jobID).Delete(&domain.JobSpeaker{}).Error; err != nil {
+			return err
+		}
+
+		if len(speakers) == 0 {
+			return nil
+		}
+
+		return tx.Create(&speakers).Error
+	})
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+var ErrTokenAlreadyRotated = errors.New("refresh token already rotated")
+
+type TokenRepository struct{}
+
+func NewTokenRepository() *TokenRepository {
+	return &TokenRepository{}
+}
+
+func (r *TokenRepository) Create(token *domain.RefreshToken) error {
+	return config.DB.Create(token).Error
+}
+
+func (r *TokenRepository) FindByHash(hash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+
+	result := config.DB.Where("token_hash = ?", hash).First(&token)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("refresh token not found")
+		}
+		return nil, result.Error
+	}
+
+	return &token, nil
+}
+
+func (r *TokenRepository) Rotate(current *domain.RefreshToken, next *domain.RefreshToken) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", current.ID).
+			Update("revoked_at", time.Now())
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrTokenAlreadyRotated
+		}
+
+		return tx.Create(next).Error
+	})
+}
+
+func (r *TokenRepository) RevokeFamily(familyID string) ([]domain.RefreshToken, error) {
+	return r.revoke("family_id = ?", familyID)
+}
+
+func (r *TokenRepository) RevokeAllForUser(userID uint) ([]domain.RefreshToken, error) {
+	return r.revoke("user_id = ?", userID)
+}
+
+func (r *TokenRepository) revoke(query string, value interface{}) ([]domain.RefreshToken, error) {
+	var tokens []domain.RefreshToken
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(query, value).Where("access_expires_at > ?", time.Now()).Find(&tokens).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.RefreshToken{}).
+			Where(query, value).
+			Where("revoked_at IS NULL").
+			Update("revoked_at", time.Now()).Error
+	})
+
+	return tokens, err
+}
+
+func (r *TokenRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := config.DB.Where("expires_at < ?", before).Delete(&domain.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
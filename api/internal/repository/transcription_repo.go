@@ -20,6 +20,16 @@ func (r *TranscriptionRepository) Create(job *domain.TranscriptionJob) error {
 	return result.Error
 }
 
+func (r *TranscriptionRepository) CreateWithOutbox(job *domain.TranscriptionJob, msg *domain.OutboxMessage) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(msg).Error
+	})
+}
+
 func (r *TranscriptionRepository) FindByID(jobID string) (*domain.TranscriptionJob, error) {
 	var job domain.TranscriptionJob
 
@@ -35,20 +45,106 @@ func (r *TranscriptionRepository) FindByID(jobID string) (*domain.TranscriptionJ
 	return &job, nil
 }
 
-func (r *TranscriptionRepository) FindByUserID(userID uint, page, pageSize int) ([]domain.TranscriptionJob, int64, error) {
+func (r *TranscriptionRepository) FindByIDs(jobIDs []string) ([]domain.TranscriptionJob, error) {
+	var jobs []domain.TranscriptionJob
+
+	result := config.DB.Where("id IN ?", jobIDs).Find(&jobs)
+
+	return jobs, result.Error
+}
+
+func (r *TranscriptionRepository) FindByScope(scope domain.JobScope, page, pageSize int) ([]domain.TranscriptionJob, int64, error) {
 	var jobs []domain.TranscriptionJob
 	var total int64
 
 	offset := (page - 1) * pageSize
 
-	config.DB.Model(&domain.TranscriptionJob{}).Where("user_id = ?", userID).Count(&total)
+	query := config.DB.Model(&domain.TranscriptionJob{}).Where(scopeCondition(scope, config.DB))
+
+	query = query.Session(&gorm.Session{})
+	query.Count(&total)
+
+	result := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&jobs)
+
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return jobs, total, nil
+}
+
+// FindStale returns up to limit jobs that have been in status since before
+// the given time, oldest first.
+func (r *TranscriptionRepository) FindStale(status domain.JobStatus, before time.Time, limit int) ([]domain.TranscriptionJob, error) {
+	var jobs []domain.TranscriptionJob
 
-	result := config.DB.Where("user_id = ?", userID).
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(pageSize).
+	result := config.DB.Where("status = ? AND updated_at < ?", status, before).
+		Order("updated_at ASC").
+		Limit(limit).
 		Find(&jobs)
 
+	return jobs, result.Error
+}
+
+func (r *TranscriptionRepository) UpdateWorkspace(jobID string, workspaceID *uint) error {
+	return config.DB.Model(&domain.TranscriptionJob{}).
+		Where("id = ?", jobID).
+		Update("workspace_id", workspaceID).Error
+}
+
+// scopeCondition matches jobs created by scope.UserID or shared with one of
+// scope.WorkspaceIDs. A zero UserID restricts the match to the workspaces.
+func scopeCondition(scope domain.JobScope, db *gorm.DB) *gorm.DB {
+	cond := db.Session(&gorm.Session{NewDB: true})
+
+	if scope.UserID != 0 {
+		cond = cond.Or("user_id = ?", scope.UserID)
+	}
+
+	if len(scope.WorkspaceIDs) > 0 {
+		cond = cond.Or("workspace_id IN ?", scope.WorkspaceIDs)
+	}
+
+	if scope.UserID == 0 && len(scope.WorkspaceIDs) == 0 {
+		cond = cond.Where("1 = 0")
+	}
+
+	return cond
+}
+
+func (r *TranscriptionRepository) FindAll(filter domain.JobFilter, page, pageSize int) ([]domain.TranscriptionJob, int64, error) {
+	var jobs []domain.TranscriptionJob
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	query := config.DB.Model(&domain.TranscriptionJob{})
+
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if filter.FileName != "" {
+		query = query.Where("file_name LIKE ?", "%"+filter.FileName+"%")
+	}
+
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	query = query.Session(&gorm.Session{})
+	query.Count(&total)
+
+	result := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&jobs)
+
 	if result.Error != nil {
 		return nil, 0, result.Error
 	}
@@ -56,11 +152,33 @@ func (r *TranscriptionRepository) FindByUserID(userID uint, page, pageSize int)
 	return jobs, total, nil
 }
 
-func (r *TranscriptionRepository) UpdateStatus(jobID, status string, text, errorMsg *string, segments *string, duration *float64) error {
-	updates := map[string]interface{}{
-		"status": status,
+func (r *TranscriptionRepository) CountByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Total  int64
+	}
+
+	result := config.DB.Model(&domain.TranscriptionJob{}).
+		Select("status, COUNT(*) AS total").
+		Group("status").
+		Scan(&rows)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	counts := make(map[string]int64, len(rows))
+
+	for _, row := range rows {
+		counts[row.Status] = row.Total
 	}
 
+	return counts, nil
+}
+
+func (r *TranscriptionRepository) UpdateStatus(jobID string, status domain.JobStatus, text, errorMsg *string, segments *string, duration *float64) error {
+	updates := map[string]interface{}{}
+
 	if text != nil {
 		updates["text"] = *text
 	}
@@ -77,11 +195,47 @@ func (r *TranscriptionRepository) UpdateStatus(jobID, status string, text, error
 		updates["duration"] = *duration
 	}
 
-	if status == "done" || status == "failed" {
+	if status == domain.JobStatusDone || status == domain.JobStatusFailed {
 		now := time.Now()
 		updates["completed_at"] = now
 	}
 
+	return r.Transition(jobID, status, updates)
+}
+
+// Transition moves a job to status and applies updates in the same
+// statement, provided the state machine allows the move from the job's
+// current status. The check happens in SQL, so a concurrent change that gets
+// there first makes this one fail with a *domain.TransitionError.
+func (r *TranscriptionRepository) Transition(jobID string, status domain.JobStatus, updates map[string]interface{}) error {
+	if updates == nil {
+		updates = make(map[string]interface{})
+	}
+
+	updates["status"] = status
+
+	result := config.DB.Model(&domain.TranscriptionJob{}).
+		Where("id = ? AND status IN ?", jobID, domain.JobStatusesBefore(status)).
+		Updates(updates)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	job, err := r.FindByID(jobID)
+
+	if err != nil {
+		return err
+	}
+
+	return &domain.TransitionError{From: job.Status, To: status}
+}
+
+func (r *TranscriptionRepository) Update(jobID string, updates map[string]interface{}) error {
 	result := config.DB.Model(&domain.TranscriptionJob{}).Where("id = ?", jobID).Updates(updates)
 	return result.Error
 }
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"errors"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+var ErrOffsetMismatch = errors.New("upload offset mismatch")
+
+type UploadRepository struct{}
+
+func NewUploadRepository() *UploadRepository {
+	return &UploadRepository{}
+}
+
+func (r *UploadRepository) Create(upload *domain.Upload) error {
+	return config.DB.Create(upload).Error
+}
+
+func (r *UploadRepository) FindByID(uploadID string) (*domain.Upload, error) {
+	var upload domain.Upload
+
+	result := config.DB.Where("id = ?", uploadID).First(&upload)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("upload not found")
+		}
+		return nil, result.Error
+	}
+
+	return &upload, nil
+}
+
+func (r *UploadRepository) AppendChunk(upload *domain.Upload, chunk *domain.UploadChunk) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Upload{}).
+			Where("id = ? AND byte_offset = ? AND completed_at IS NULL", upload.ID, chunk.Offset).
+			Update("byte_offset", chunk.Offset+chunk.Size)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrOffsetMismatch
+		}
+
+		return tx.Create(chunk).Error
+	})
+}
+
+func (r *UploadRepository) Chunks(uploadID string) ([]domain.UploadChunk, error) {
+	var chunks []domain.UploadChunk
+
+	result := config.DB.Where("upload_id = ?", uploadID).Order("byte_offset ASC").Find(&chunks)
+
+	return chunks, result.Error
+}
+
+func (r *UploadRepository) Complete(uploadID string, job *domain.TranscriptionJob, msg *domain.OutboxMessage) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&domain.Upload{}).
+			Where("id = ? AND completed_at IS NULL", uploadID).
+			Updates(map[string]interface{}{
+				"job_id":       job.ID,
+				"completed_at": now,
+			})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("upload already completed")
+		}
+
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(msg).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("upload_id = ?", uploadID).Delete(&domain.UploadChunk{}).Error
+	})
+}
+
+func (r *UploadRepository) Delete(uploadID string) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("upload_id = ?", uploadID).Delete(&domain.UploadChunk{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&domain.Upload{}, "id = ?", uploadID).Error
+	})
+}
+
+func (r *UploadRepository) FindExpired(before time.Time, limit int) ([]domain.Upload, error) {
+	var uploads []domain.Upload
+
+	result := config.DB.Where("expires_at < ?", before).Limit(limit).Find(&uploads)
+
+	return uploads, result.Error
+}
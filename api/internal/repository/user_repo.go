@@ -26,6 +26,7 @@ func (r *UserRepository) Create(user *domain.RegisterRequest) (*domain.User, err
 		Name:     user.Name,
 		Email:    user.Email,
 		Password: string(hashedPassword),
+		Role:     domain.RoleUser,
 	}
 
 	result := config.DB.Create(&newUser)
@@ -40,15 +41,35 @@ func (r *UserRepository) Create(user *domain.RegisterRequest) (*domain.User, err
 	return &newUser, nil
 }
 
-func (r *UserRepository) GetAll(page, pageSize int) ([]domain.User, int64, error) {
+func (r *UserRepository) GetAll(filter domain.UserFilter, page, pageSize int) ([]domain.User, int64, error) {
 	var users []domain.User
 	var total int64
 
 	offset := (page - 1) * pageSize
 
-	config.DB.Model(&domain.User{}).Count(&total)
+	query := config.DB.Model(&domain.User{})
 
-	result := config.DB.Offset(offset).Limit(pageSize).Find(&users)
+	if filter.Query != "" {
+		like := "%" + filter.Query + "%"
+		query = query.Where("(name LIKE ? OR email LIKE ?)", like, like)
+	}
+
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+
+	if filter.Disabled != nil {
+		if *filter.Disabled {
+			query = query.Where("disabled_at IS NOT NULL")
+		} else {
+			query = query.Where("disabled_at IS NULL")
+		}
+	}
+
+	query = query.Session(&gorm.Session{})
+	query.Count(&total)
+
+	result := query.Order("id ASC").Offset(offset).Limit(pageSize).Find(&users)
 
 	if result.Error != nil {
 		return nil, 0, result.Error
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"errors"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository struct{}
+
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{}
+}
+
+func (r *WebhookRepository) Create(webhook *domain.Webhook) error {
+	return config.DB.Create(webhook).Error
+}
+
+func (r *WebhookRepository) FindByID(webhookID uint) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+
+	result := config.DB.Where("id = ?", webhookID).First(&webhook)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook not found")
+		}
+		return nil, result.Error
+	}
+
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) FindByUserID(userID uint) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+
+	result := config.DB.Where("user_id = ?", userID).Order("id ASC").Find(&webhooks)
+
+	return webhooks, result.Error
+}
+
+func (r *WebhookRepository) FindActiveByUserID(userID uint) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+
+	result := config.DB.Where("user_id = ? AND active = ?", userID, true).Find(&webhooks)
+
+	return webhooks, result.Error
+}
+
+func (r *WebhookRepository) Update(webhookID uint, updates map[string]interface{}) error {
+	return config.DB.Model(&domain.Webhook{}).Where("id = ?", webhookID).Updates(updates).Error
+}
+
+func (r *WebhookRepository) Delete(webhookID uint) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", webhookID).Delete(&domain.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&domain.Webhook{}, webhookID).Error
+	})
+}
+
+func (r *WebhookRepository) CreateDelivery(delivery *domain.WebhookDelivery) (bool, error) {
+	result := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(delivery)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *WebhookRepository) FindDelivery(webhookID, deliveryID uint) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+
+	result := config.DB.Where("id = ? AND webhook_id = ?", deliveryID, webhookID).First(&delivery)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("delivery not found")
+		}
+		return nil, result.Error
+	}
+
+	return &delivery, nil
+}
+
+func (r *WebhookRepository) FindDeliveries(webhookID uint, page, pageSize int) ([]domain.WebhookDelivery, int64, error) {
+	var deliveries []domain.WebhookDelivery
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	config.DB.Model(&domain.WebhookDelivery{}).Where("webhook_id = ?", webhookID).Count(&total)
+
+	result := config.DB.Where("webhook_id = ?", webhookID).
+		Order("id DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&deliveries)
+
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return deliveries, total, nil
+}
+
+func (r *WebhookRepository) ClaimDue(limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", domain.WebhookDeliveryPending, time.Now()).
+			Order("id ASC").
+			Limit(limit).
+			Find(&deliveries)
+
+		if result.Error != nil || len(deliveries) == 0 {
+			return result.Error
+		}
+
+		ids := make([]uint, len(deliveries))
+
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+
+		return tx.Model(&domain.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", time.Now().Add(lease)).Error
+	})
+
+	return deliveries, err
+}
+
+func (r *WebhookRepository) UpdateDelivery(deliveryID uint, updates map[string]interface{}) error {
+	return config.DB.Model(&domain.WebhookDelivery{}).Where("id = ?", deliveryID).Updates(updates).Error
+}
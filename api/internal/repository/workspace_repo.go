@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"errors"
+	"transcribe/config"
+	"transcribe/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	ErrMemberNotFound    = errors.New("member not found")
+	ErrMemberExists      = errors.New("user is already a member")
+)
+
+type WorkspaceRepository struct{}
+
+func NewWorkspaceRepository() *WorkspaceRepository {
+	return &WorkspaceRepository{}
+}
+
+func (r *WorkspaceRepository) Create(workspace *domain.Workspace) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&domain.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      workspace.CreatedBy,
+			Role:        domain.WorkspaceRoleOwner,
+		}).Error
+	})
+}
+
+func (r *WorkspaceRepository) FindByID(id uint) (*domain.Workspace, error) {
+	var workspace domain.Workspace
+
+	result := config.DB.First(&workspace, id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &workspace, nil
+}
+
+func (r *WorkspaceRepository) FindMemberships(userID uint) ([]domain.WorkspaceMember, error) {
+	var members []domain.WorkspaceMember
+
+	result := config.DB.Preload("Workspace").
+		Where("user_id = ?", userID).
+		Order("workspace_id ASC").
+		Find(&members)
+
+	return members, result.Error
+}
+
+func (r *WorkspaceRepository) WorkspaceIDs(userID uint) ([]uint, error) {
+	var ids []uint
+
+	result := config.DB.Model(&domain.WorkspaceMember{}).
+		Where("user_id = ?", userID).
+		Pluck("workspace_id", &ids)
+
+	return ids, result.Error
+}
+
+func (r *WorkspaceRepository) MemberUserIDs(workspaceID uint) ([]uint, error) {
+	var ids []uint
+
+	result := config.DB.Model(&domain.WorkspaceMember{}).
+		Where("workspace_id = ?", workspaceID).
+		Pluck("user_id", &ids)
+
+	return ids, result.Error
+}
+
+func (r *WorkspaceRepository) Update(id uint, updates map[string]interface{}) error {
+	return config.DB.Model(&domain.Workspace{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// Delete detaches the workspace's jobs, which stay visible to their creators.
+func (r *WorkspaceRepository) Delete(id uint) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.TranscriptionJob{}).
+			Where("workspace_id = ?", id).
+			Update("workspace_id", nil).Error
+
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("workspace_id = ?", id).Delete(&domain.WorkspaceMember{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&domain.Workspace{}, id).Error
+	})
+}
+
+func (r *WorkspaceRepository) FindMember(workspaceID, userID uint) (*domain.WorkspaceMember, error) {
+	var member domain.WorkspaceMember
+
+	result := config.DB.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &member, nil
+}
+
+func (r *WorkspaceRepository) FindMembers(workspaceID uint) ([]domain.WorkspaceMember, error) {
+	var members []domain.WorkspaceMember
+
+	result := config.DB.Preload("User").
+		Where("workspace_id = ?", workspaceID).
+		Order("created_at ASC").
+		Find(&members)
+
+	return members, result.Error
+}
+
+func (r *WorkspaceRepository) AddMember(member *domain.WorkspaceMember) error {
+	result := config.DB.Create(member)
+
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return ErrMemberExists
+	}
+
+	return result.Error
+}
+
+func (r *WorkspaceRepository) UpdateMemberRole(workspaceID, userID uint, role string) error {
+	result := config.DB.Model(&domain.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Update("role", role)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) RemoveMember(workspaceID, userID uint) error {
+	result := config.DB.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Delete(&domain.WorkspaceMember{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) CountOwners(workspaceID uint) (int64, error) {
+	var total int64
+
+	result := config.DB.Model(&domain.WorkspaceMember{}).
+		Where("workspace_id = ? AND role = ?", workspaceID, domain.WorkspaceRoleOwner).
+		Count(&total)
+
+	return total, result.Error
+}
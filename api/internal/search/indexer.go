@@ -0,0 +1,109 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+)
+
+const indexBatchSize = 50
+
+type Indexer struct {
+	segmentRepo *repository.SegmentRepository
+	interval    time.Duration
+}
+
+func NewIndexer() *Indexer {
+	return &Indexer{
+		segmentRepo: repository.NewSegmentRepository(),
+		interval:    config.AppConfig.SearchIndexInterval,
+	}
+}
+
+func (i *Indexer) Run(ctx context.Context) {
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	logger.Log.Infof("search indexer started (interval %s)", i.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Log.Info("search indexer stopped")
+			return
+		case <-ticker.C:
+			i.indexPending()
+		}
+	}
+}
+
+func (i *Indexer) indexPending() {
+	jobs, err := i.segmentRepo.FindUnindexedJobs(indexBatchSize)
+
+	if err != nil {
+		logger.Log.Errorf("failed to fetch jobs to index: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		log := logger.Log.WithField("job_id", job.ID)
+
+		rows := BuildSegments(&job)
+
+		if err := i.segmentRepo.ReplaceForJob(job.ID, job.Revision, rows); err != nil {
+			if errors.Is(err, repository.ErrIndexStale) {
+				log.Debug("transcript was edited while indexing, retrying on the next run")
+				continue
+			}
+
+			log.Errorf("failed to index job segments: %v", err)
+			continue
+		}
+
+		log.Debugf("indexed %d segments for search", len(rows))
+	}
+}
+
+func BuildSegments(job *domain.TranscriptionJob) []domain.TranscriptSegment {
+	var segments []domain.Segment
+
+	if job.Segments != "" {
+		if err := json.Unmarshal([]byte(job.Segments), &segments); err != nil {
+			logger.Log.WithField("job_id", job.ID).Warnf("failed to decode segments for indexing: %v", err)
+		}
+	}
+
+	rows := make([]domain.TranscriptSegment, 0, len(segments))
+
+	for _, seg := range segments {
+		if seg.Text == "" {
+			continue
+		}
+
+		rows = append(rows, domain.TranscriptSegment{
+			JobID:     job.ID,
+			UserID:    job.UserID,
+			SegmentID: seg.ID,
+			StartTime: seg.StartTime,
+			EndTime:   seg.EndTime,
+			Speaker:   seg.Speaker,
+			Text:      seg.Text,
+		})
+	}
+
+	if len(rows) == 0 && job.Text != "" {
+		rows = append(rows, domain.TranscriptSegment{
+			JobID:   job.ID,
+			UserID:  job.UserID,
+			EndTime: job.Duration,
+			Text:    job.Text,
+		})
+	}
+
+	return rows
+}
@@ -0,0 +1,244 @@
+package search
+
+import (
+	"errors"
+	"html"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const snippetLength = 160
+
+// minTokenSize matches InnoDB's default innodb_ft_min_token_size. Shorter
+// words are not in the full-text index.
+const minTokenSize = 3
+
+var (
+	ErrEmptyQuery    = errors.New("search query is empty")
+	ErrNoSearchTerms = errors.New("search query only contains ignored words")
+)
+
+// stopwords is InnoDB's default full-text stopword list. These words are not
+// in the index either, so a required term built from one matches nothing.
+var stopwords = map[string]bool{
+	"a": true, "about": true, "an": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "com": true, "de": true, "en": true, "for": true,
+	"from": true, "how": true, "i": true, "in": true, "is": true, "it": true,
+	"la": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"this": true, "to": true, "was": true, "what": true, "when": true,
+	"where": true, "who": true, "will": true, "with": true, "und": true,
+	"www": true,
+}
+
+type Query struct {
+	Terms   []string
+	Phrases []string
+	// Ignored lists the terms left out of the query because they are
+	// stopwords or too short to be indexed.
+	Ignored []string
+}
+
+func ParseQuery(raw string) (Query, error) {
+	var q Query
+
+	parts := strings.Split(raw, `"`)
+
+	for i, part := range parts {
+		if i%2 == 1 {
+			if phrase := strings.Join(tokenize(part), " "); phrase != "" {
+				q.Phrases = append(q.Phrases, phrase)
+			}
+			continue
+		}
+
+		for _, term := range tokenize(part) {
+			if searchable(term) {
+				q.Terms = append(q.Terms, term)
+			} else if !slices.Contains(q.Ignored, term) {
+				q.Ignored = append(q.Ignored, term)
+			}
+		}
+	}
+
+	if len(q.Terms) == 0 && len(q.Phrases) == 0 {
+		if len(q.Ignored) > 0 {
+			return q, ErrNoSearchTerms
+		}
+		return q, ErrEmptyQuery
+	}
+
+	return q, nil
+}
+
+func (q Query) Boolean() string {
+	clauses := make([]string, 0, len(q.Terms)+len(q.Phrases))
+
+	for _, phrase := range q.Phrases {
+		clauses = append(clauses, `+"`+phrase+`"`)
+	}
+
+	for _, term := range q.Terms {
+		clauses = append(clauses, "+"+term+"*")
+	}
+
+	return strings.Join(clauses, " ")
+}
+
+func (q Query) Highlight(text string) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	var spans [][2]int
+
+	for _, phrase := range q.Phrases {
+		spans = append(spans, findAll(lower, []rune(phrase), false)...)
+	}
+
+	for _, term := range q.Terms {
+		spans = append(spans, findAll(lower, []rune(term), true)...)
+	}
+
+	spans = mergeSpans(spans)
+
+	start, end := 0, len(runes)
+
+	if len(runes) > snippetLength {
+		if len(spans) > 0 {
+			start = max(0, spans[0][0]-snippetLength/3)
+		}
+		end = min(len(runes), start+snippetLength)
+		start = max(0, end-snippetLength)
+	}
+
+	var b strings.Builder
+
+	if start > 0 {
+		b.WriteString("…")
+	}
+
+	pos := start
+
+	for _, span := range spans {
+		if span[1] <= start || span[0] >= end {
+			continue
+		}
+
+		spanStart := max(span[0], start)
+		spanEnd := min(span[1], end)
+
+		b.WriteString(html.EscapeString(string(runes[pos:spanStart])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[spanStart:spanEnd])))
+		b.WriteString("</mark>")
+
+		pos = spanEnd
+	}
+
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
+
+func searchable(term string) bool {
+	return utf8.RuneCountInString(term) >= minTokenSize && !stopwords[term]
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
+
+func findAll(haystack, needle []rune, prefix bool) [][2]int {
+	var spans [][2]int
+
+	if len(needle) == 0 {
+		return spans
+	}
+
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		if i > 0 && isWordRune(haystack[i-1]) {
+			continue
+		}
+
+		end, ok := matchAt(haystack, needle, i)
+
+		if !ok {
+			continue
+		}
+
+		if prefix {
+			for end < len(haystack) && isWordRune(haystack[end]) {
+				end++
+			}
+		} else if end < len(haystack) && isWordRune(haystack[end]) {
+			continue
+		}
+
+		spans = append(spans, [2]int{i, end})
+		i = end - 1
+	}
+
+	return spans
+}
+
+func matchAt(haystack, needle []rune, at int) (int, bool) {
+	j := at
+
+	for k := 0; k < len(needle); k++ {
+		if needle[k] == ' ' {
+			if j >= len(haystack) || isWordRune(haystack[j]) {
+				return 0, false
+			}
+			for j < len(haystack) && !isWordRune(haystack[j]) {
+				j++
+			}
+			continue
+		}
+
+		if j >= len(haystack) || haystack[j] != needle[k] {
+			return 0, false
+		}
+		j++
+	}
+
+	return j, true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\''
+}
+
+func mergeSpans(spans [][2]int) [][2]int {
+	if len(spans) < 2 {
+		return spans
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	merged := [][2]int{spans[0]}
+
+	for _, span := range spans[1:] {
+		last := &merged[len(merged)-1]
+
+		if span[0] <= last[1] {
+			last[1] = max(last[1], span[1])
+			continue
+		}
+
+		merged = append(merged, span)
+	}
+
+	return merged
+}
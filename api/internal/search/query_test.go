@@ -0,0 +1,184 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    Query
+		wantErr error
+	}{
+		{
+			name: "terms are lowercased",
+			raw:  "Rapat Anggaran",
+			want: Query{Terms: []string{"rapat", "anggaran"}},
+		},
+		{
+			name: "quoted phrase",
+			raw:  `"rapat anggaran" 2025`,
+			want: Query{Terms: []string{"2025"}, Phrases: []string{"rapat anggaran"}},
+		},
+		{
+			name: "punctuation splits terms",
+			raw:  "budget,review; q3-plan",
+			want: Query{Terms: []string{"budget", "review", "plan"}, Ignored: []string{"q3"}},
+		},
+		{
+			name: "apostrophes stay in the term",
+			raw:  "don't",
+			want: Query{Terms: []string{"don't"}},
+		},
+		{
+			name: "unterminated quote is a phrase",
+			raw:  `hasil "transkripsi audio`,
+			want: Query{Terms: []string{"hasil"}, Phrases: []string{"transkripsi audio"}},
+		},
+		{
+			name: "stopwords and short words are ignored once",
+			raw:  "the budget of the year",
+			want: Query{Terms: []string{"budget", "year"}, Ignored: []string{"the", "of"}},
+		},
+		{
+			name: "stopwords inside phrases are kept",
+			raw:  `"state of the art"`,
+			want: Query{Phrases: []string{"state of the art"}},
+		},
+		{
+			name: "multibyte words count runes",
+			raw:  "über ça",
+			want: Query{Terms: []string{"über"}, Ignored: []string{"ça"}},
+		},
+		{
+			name:    "empty",
+			raw:     "  ",
+			wantErr: ErrEmptyQuery,
+		},
+		{
+			name:    "empty phrase",
+			raw:     `""`,
+			wantErr: ErrEmptyQuery,
+		},
+		{
+			name:    "only ignored words",
+			raw:     "is it",
+			want:    Query{Ignored: []string{"is", "it"}},
+			wantErr: ErrNoSearchTerms,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQuery(tt.raw)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseQuery(%q) error = %v, want %v", tt.raw, err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil || len(tt.want.Ignored) > 0 {
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("ParseQuery(%q) = %#v, want %#v", tt.raw, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryBoolean(t *testing.T) {
+	tests := []struct {
+		name  string
+		query Query
+		want  string
+	}{
+		{name: "terms", query: Query{Terms: []string{"rapat", "anggaran"}}, want: "+rapat* +anggaran*"},
+		{name: "phrase first", query: Query{Terms: []string{"2025"}, Phrases: []string{"rapat anggaran"}}, want: `+"rapat anggaran" +2025*`},
+		{name: "ignored words are left out", query: Query{Terms: []string{"budget"}, Ignored: []string{"the"}}, want: "+budget*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Boolean(); got != tt.want {
+				t.Fatalf("Boolean() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryHighlight(t *testing.T) {
+	long := "Pembukaan rapat oleh ketua panitia dan perkenalan semua peserta yang hadir pagi ini di ruang utama gedung. " +
+		"Selanjutnya kita membahas anggaran tahun depan secara rinci bersama tim keuangan dan perwakilan dari setiap divisi."
+
+	tests := []struct {
+		name  string
+		query string
+		text  string
+		want  string
+	}{
+		{
+			name:  "prefix match covers the whole word",
+			query: "transk",
+			text:  "Ini hasil transkripsi.",
+			want:  "Ini hasil <mark>transkripsi</mark>.",
+		},
+		{
+			name:  "case insensitive",
+			query: "rapat",
+			text:  "Rapat dimulai.",
+			want:  "<mark>Rapat</mark> dimulai.",
+		},
+		{
+			name:  "term only matches at a word start",
+			query: "apat",
+			text:  "rapat",
+			want:  "rapat",
+		},
+		{
+			name:  "phrase matches whole words across punctuation",
+			query: `"hasil transkripsi"`,
+			text:  "Hasil, transkripsi dan hasil transkripsinya.",
+			want:  "<mark>Hasil, transkripsi</mark> dan hasil transkripsinya.",
+		},
+		{
+			name:  "overlapping matches are merged",
+			query: `"rapat anggaran" anggaran`,
+			text:  "rapat anggaran",
+			want:  "<mark>rapat anggaran</mark>",
+		},
+		{
+			name:  "text is escaped",
+			query: "tag",
+			text:  "<b>tag</b> & more",
+			want:  "&lt;b&gt;<mark>tag</mark>&lt;/b&gt; &amp; more",
+		},
+		{
+			name:  "no match",
+			query: "budget",
+			text:  "rapat",
+			want:  "rapat",
+		},
+		{
+			name:  "long text is cut around the first match",
+			query: "anggaran",
+			text:  long,
+			want: "…a yang hadir pagi ini di ruang utama gedung. Selanjutnya kita membahas <mark>anggaran</mark> " +
+				"tahun depan secara rinci bersama tim keuangan dan perwakilan dari setiap divisi.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q): %v", tt.query, err)
+			}
+
+			if got := q.Highlight(tt.text); got != tt.want {
+				t.Fatalf("Highlight(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package transcript
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"transcribe/internal/domain"
+)
+
+const MaxOperations = 500
+
+// Apply runs ops against a copy of segments and returns the edited
+// transcript. Segment ids are renumbered from zero after every operation, so
+// later operations in the same batch refer to the renumbered ids.
+func Apply(segments []domain.Segment, ops []domain.SegmentOperation) ([]domain.Segment, error) {
+	if len(ops) == 0 {
+		return nil, errors.New("at least one operation is required")
+	}
+
+	if len(ops) > MaxOperations {
+		return nil, fmt.Errorf("at most %d operations are allowed per request", MaxOperations)
+	}
+
+	result := make([]domain.Segment, len(segments))
+
+	for i, seg := range segments {
+		seg.Words = append([]domain.Word(nil), seg.Words...)
+		result[i] = seg
+	}
+
+	for i, op := range ops {
+		var err error
+
+		switch op.Op {
+		case domain.OpEditText:
+			err = editText(result, op)
+		case domain.OpSplit:
+			result, err = split(result, op)
+		case domain.OpMerge:
+			result, err = merge(result, op)
+		case domain.OpRetime:
+			err = retime(result, op)
+		case domain.OpRelabelSpeaker:
+			err = relabelSpeaker(result, op)
+		default:
+			err = errors.New("unknown op. Allowed: edit_text, split, merge, retime, relabel_speaker")
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s): %w", i, op.Op, err)
+		}
+
+		for j := range result {
+			result[j].ID = j
+		}
+	}
+
+	return result, nil
+}
+
+// JoinText rebuilds the full transcript text from its segments.
+func JoinText(segments []domain.Segment) string {
+	parts := make([]string, 0, len(segments))
+
+	for _, seg := range segments {
+		if text := strings.TrimSpace(seg.Text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func find(segments []domain.Segment, id *int) (int, error) {
+	if id == nil {
+		return 0, errors.New("segment_id is required")
+	}
+
+	for i, seg := range segments {
+		if seg.ID == *id {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("segment %d not found", *id)
+}
+
+func editText(segments []domain.Segment, op domain.SegmentOperation) error {
+	idx, err := find(segments, op.SegmentID)
+
+	if err != nil {
+		return err
+	}
+
+	text := strings.TrimSpace(op.Text)
+
+	if text == "" {
+		return errors.New("text is required")
+	}
+
+	setText(&segments[idx], text)
+
+	return nil
+}
+
+func split(segments []domain.Segment, op domain.SegmentOperation) ([]domain.Segment, error) {
+	idx, err := find(segments, op.SegmentID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	seg := segments[idx]
+
+	if op.At <= seg.StartTime || op.At >= seg.EndTime {
+		return nil, fmt.Errorf("at must be between %.3f and %.3f", seg.StartTime, seg.EndTime)
+	}
+
+	first, second := seg, seg
+	first.EndTime = op.At
+	second.StartTime = op.At
+	first.Words, second.Words = nil, nil
+
+	for _, w := range seg.Words {
+		if w.Start < op.At {
+			first.Words = append(first.Words, w)
+		} else {
+			second.Words = append(second.Words, w)
+		}
+	}
+
+	switch {
+	case len(op.Texts) == 2:
+		a, b := strings.TrimSpace(op.Texts[0]), strings.TrimSpace(op.Texts[1])
+
+		if a == "" || b == "" {
+			return nil, errors.New("both texts must be non-empty")
+		}
+
+		setText(&first, a)
+		setText(&second, b)
+
+	case len(op.Texts) != 0:
+		return nil, errors.New("texts must contain exactly two entries")
+
+	case len(seg.Words) > 0:
+		if len(first.Words) == 0 || len(second.Words) == 0 {
+			return nil, errors.New("at must fall between two words")
+		}
+
+		first.Text = joinWords(first.Words)
+		second.Text = joinWords(second.Words)
+
+	default:
+		return nil, errors.New("texts is required for segments without word timestamps")
+	}
+
+	result := make([]domain.Segment, 0, len(segments)+1)
+	result = append(result, segments[:idx]...)
+	result = append(result, first, second)
+
+	return append(result, segments[idx+1:]...), nil
+}
+
+func merge(segments []domain.Segment, op domain.SegmentOperation) ([]domain.Segment, error) {
+	if len(op.SegmentIDs) < 2 {
+		return nil, errors.New("segment_ids must contain at least two segments")
+	}
+
+	first, err := find(segments, &op.SegmentIDs[0])
+
+	if err != nil {
+		return nil, err
+	}
+
+	merged := segments[first]
+	texts := []string{strings.TrimSpace(merged.Text)}
+
+	for i, id := range op.SegmentIDs[1:] {
+		idx, err := find(segments, &id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if idx != first+i+1 {
+			return nil, errors.New("segment_ids must be consecutive and in order")
+		}
+
+		seg := segments[idx]
+
+		merged.EndTime = seg.EndTime
+		merged.Words = append(merged.Words, seg.Words...)
+		texts = append(texts, strings.TrimSpace(seg.Text))
+	}
+
+	merged.Text = strings.Join(texts, " ")
+
+	last := first + len(op.SegmentIDs)
+
+	result := make([]domain.Segment, 0, len(segments)-len(op.SegmentIDs)+1)
+	result = append(result, segments[:first]...)
+	result = append(result, merged)
+
+	return append(result, segments[last:]...), nil
+}
+
+func retime(segments []domain.Segment, op domain.SegmentOperation) error {
+	idx, err := find(segments, op.SegmentID)
+
+	if err != nil {
+		return err
+	}
+
+	seg := &segments[idx]
+	start, end := seg.StartTime, seg.EndTime
+
+	if op.Start != nil {
+		start = *op.Start
+	}
+
+	if op.End != nil {
+		end = *op.End
+	}
+
+	if start < 0 || end <= start {
+		return errors.New("start must be non-negative and before end")
+	}
+
+	if (idx > 0 && start < segments[idx-1].EndTime) || (idx < len(segments)-1 && end > segments[idx+1].StartTime) {
+		return errors.New("segment would overlap its neighbours")
+	}
+
+	seg.StartTime, seg.EndTime = start, end
+
+	words := seg.Words[:0]
+
+	for _, w := range seg.Words {
+		if w.End <= start || w.Start >= end {
+			continue
+		}
+
+		w.Start = max(w.Start, start)
+		w.End = min(w.End, end)
+		words = append(words, w)
+	}
+
+	seg.Words = words
+
+	return nil
+}
+
+func relabelSpeaker(segments []domain.Segment, op domain.SegmentOperation) error {
+	if op.Speaker == nil {
+		return errors.New("speaker is required")
+	}
+
+	speaker := strings.TrimSpace(*op.Speaker)
+
+	if len(speaker) > 100 {
+		return errors.New("speaker must be at most 100 characters")
+	}
+
+	if op.SegmentID != nil {
+		idx, err := find(segments, op.SegmentID)
+
+		if err != nil {
+			return err
+		}
+
+		segments[idx].Speaker = speaker
+
+		return nil
+	}
+
+	if op.From == "" {
+		return errors.New("segment_id or from is required")
+	}
+
+	matched := 0
+
+	for i := range segments {
+		if segments[i].Speaker == op.From {
+			segments[i].Speaker = speaker
+			matched++
+		}
+	}
+
+	if matched == 0 {
+		return fmt.Errorf("no segments with speaker %q", op.From)
+	}
+
+	return nil
+}
+
+// setText replaces the text of seg. Word timestamps survive when the new
+// text has the same number of words; otherwise they no longer line up and
+// are dropped.
+func setText(seg *domain.Segment, text string) {
+	seg.Text = text
+
+	fields := strings.Fields(text)
+
+	if len(fields) != len(seg.Words) {
+		seg.Words = nil
+		return
+	}
+
+	for i, field := range fields {
+		if strings.HasPrefix(seg.Words[i].Text, " ") {
+			field = " " + field
+		}
+
+		seg.Words[i].Text = field
+	}
+}
+
+func joinWords(words []domain.Word) string {
+	var b strings.Builder
+
+	for _, w := range words {
+		if b.Len() > 0 && !strings.HasPrefix(w.Text, " ") {
+			b.WriteByte(' ')
+		}
+
+		b.WriteString(w.Text)
+	}
+
+	return strings.TrimSpace(b.String())
+}
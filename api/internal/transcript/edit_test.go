@@ -0,0 +1,257 @@
+package transcript
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"transcribe/internal/domain"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func testSegments() []domain.Segment {
+	return []domain.Segment{
+		{
+			ID: 0, StartTime: 0, EndTime: 2, Text: "Selamat pagi semua.", Speaker: "SPEAKER_00",
+			Words: []domain.Word{
+				{Text: " Selamat", Start: 0, End: 0.6},
+				{Text: " pagi", Start: 0.7, End: 1.1},
+				{Text: " semua.", Start: 1.2, End: 2},
+			},
+		},
+		{ID: 1, StartTime: 2.5, EndTime: 4, Text: "Mari kita mulai.", Speaker: "SPEAKER_01"},
+		{ID: 2, StartTime: 4.5, EndTime: 6, Text: "Agenda pertama.", Speaker: "SPEAKER_00"},
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		ops     []domain.SegmentOperation
+		want    []domain.Segment
+		wantErr string
+	}{
+		{
+			name: "edit text keeps word timestamps when the word count matches",
+			ops:  []domain.SegmentOperation{{Op: domain.OpEditText, SegmentID: ptr(0), Text: " Selamat siang semua. "}},
+			want: func() []domain.Segment {
+				s := testSegments()
+				s[0].Text = "Selamat siang semua."
+				s[0].Words[1].Text = " siang"
+				return s
+			}(),
+		},
+		{
+			name: "edit text drops word timestamps when the word count changes",
+			ops:  []domain.SegmentOperation{{Op: domain.OpEditText, SegmentID: ptr(0), Text: "Pagi."}},
+			want: func() []domain.Segment {
+				s := testSegments()
+				s[0].Text = "Pagi."
+				s[0].Words = nil
+				return s
+			}(),
+		},
+		{
+			name: "split by word timestamps",
+			ops:  []domain.SegmentOperation{{Op: domain.OpSplit, SegmentID: ptr(0), At: 0.65}},
+			want: func() []domain.Segment {
+				s := testSegments()
+				first, second := s[0], s[0]
+				first.EndTime, first.Text, first.Words = 0.65, "Selamat", s[0].Words[:1]
+				second.ID, second.StartTime, second.Text, second.Words = 1, 0.65, "pagi semua.", s[0].Words[1:]
+				s[1].ID, s[2].ID = 2, 3
+				return []domain.Segment{first, second, s[1], s[2]}
+			}(),
+		},
+		{
+			name: "split with explicit texts",
+			ops:  []domain.SegmentOperation{{Op: domain.OpSplit, SegmentID: ptr(1), At: 3, Texts: []string{"Mari kita", "mulai."}}},
+			want: func() []domain.Segment {
+				s := testSegments()
+				first, second := s[1], s[1]
+				first.EndTime, first.Text = 3, "Mari kita"
+				second.ID, second.StartTime, second.Text = 2, 3, "mulai."
+				s[2].ID = 3
+				return []domain.Segment{s[0], first, second, s[2]}
+			}(),
+		},
+		{
+			name: "merge consecutive segments",
+			ops:  []domain.SegmentOperation{{Op: domain.OpMerge, SegmentIDs: []int{1, 2}}},
+			want: func() []domain.Segment {
+				s := testSegments()
+				s[1].EndTime = 6
+				s[1].Text = "Mari kita mulai. Agenda pertama."
+				return s[:2]
+			}(),
+		},
+		{
+			name: "retime trims words outside the new range",
+			ops:  []domain.SegmentOperation{{Op: domain.OpRetime, SegmentID: ptr(0), Start: ptr(0.8), End: ptr(1.5)}},
+			want: func() []domain.Segment {
+				s := testSegments()
+				s[0].StartTime, s[0].EndTime = 0.8, 1.5
+				s[0].Words = []domain.Word{
+					{Text: " pagi", Start: 0.8, End: 1.1},
+					{Text: " semua.", Start: 1.2, End: 1.5},
+				}
+				return s
+			}(),
+		},
+		{
+			name: "relabel one segment",
+			ops:  []domain.SegmentOperation{{Op: domain.OpRelabelSpeaker, SegmentID: ptr(1), Speaker: ptr(" Dr. Sari ")}},
+			want: func() []domain.Segment {
+				s := testSegments()
+				s[1].Speaker = "Dr. Sari"
+				return s
+			}(),
+		},
+		{
+			name: "relabel every segment of a speaker",
+			ops:  []domain.SegmentOperation{{Op: domain.OpRelabelSpeaker, From: "SPEAKER_00", Speaker: ptr("Budi")}},
+			want: func() []domain.Segment {
+				s := testSegments()
+				s[0].Speaker, s[2].Speaker = "Budi", "Budi"
+				return s
+			}(),
+		},
+		{
+			name: "later operations see renumbered ids",
+			ops: []domain.SegmentOperation{
+				{Op: domain.OpMerge, SegmentIDs: []int{0, 1}},
+				{Op: domain.OpEditText, SegmentID: ptr(1), Text: "Agenda kedua."},
+			},
+			want: func() []domain.Segment {
+				s := testSegments()
+				s[0].EndTime = 4
+				s[0].Text = "Selamat pagi semua. Mari kita mulai."
+				s[2].ID, s[2].Text = 1, "Agenda kedua."
+				return []domain.Segment{s[0], s[2]}
+			}(),
+		},
+		{name: "no operations", wantErr: "at least one operation is required"},
+		{
+			name:    "too many operations",
+			ops:     make([]domain.SegmentOperation, MaxOperations+1),
+			wantErr: "at most 500 operations",
+		},
+		{
+			name:    "unknown op",
+			ops:     []domain.SegmentOperation{{Op: "delete", SegmentID: ptr(0)}},
+			wantErr: "operation 0 (delete): unknown op",
+		},
+		{
+			name:    "missing segment",
+			ops:     []domain.SegmentOperation{{Op: domain.OpEditText, SegmentID: ptr(9), Text: "x"}},
+			wantErr: "segment 9 not found",
+		},
+		{
+			name:    "missing segment id",
+			ops:     []domain.SegmentOperation{{Op: domain.OpEditText, Text: "x"}},
+			wantErr: "segment_id is required",
+		},
+		{
+			name:    "empty text",
+			ops:     []domain.SegmentOperation{{Op: domain.OpEditText, SegmentID: ptr(0), Text: "  "}},
+			wantErr: "text is required",
+		},
+		{
+			name:    "split outside the segment",
+			ops:     []domain.SegmentOperation{{Op: domain.OpSplit, SegmentID: ptr(0), At: 2}},
+			wantErr: "at must be between",
+		},
+		{
+			name:    "split inside a word gap with no words on one side",
+			ops:     []domain.SegmentOperation{{Op: domain.OpSplit, SegmentID: ptr(0), At: 1.9}},
+			wantErr: "at must fall between two words",
+		},
+		{
+			name:    "split without words or texts",
+			ops:     []domain.SegmentOperation{{Op: domain.OpSplit, SegmentID: ptr(1), At: 3}},
+			wantErr: "texts is required",
+		},
+		{
+			name:    "split with one text",
+			ops:     []domain.SegmentOperation{{Op: domain.OpSplit, SegmentID: ptr(1), At: 3, Texts: []string{"Mari"}}},
+			wantErr: "texts must contain exactly two entries",
+		},
+		{
+			name:    "merge a single segment",
+			ops:     []domain.SegmentOperation{{Op: domain.OpMerge, SegmentIDs: []int{1}}},
+			wantErr: "at least two segments",
+		},
+		{
+			name:    "merge segments out of order",
+			ops:     []domain.SegmentOperation{{Op: domain.OpMerge, SegmentIDs: []int{0, 2}}},
+			wantErr: "consecutive and in order",
+		},
+		{
+			name:    "retime over a neighbour",
+			ops:     []domain.SegmentOperation{{Op: domain.OpRetime, SegmentID: ptr(1), End: ptr(5.0)}},
+			wantErr: "overlap its neighbours",
+		},
+		{
+			name:    "retime with end before start",
+			ops:     []domain.SegmentOperation{{Op: domain.OpRetime, SegmentID: ptr(1), Start: ptr(3.5), End: ptr(3.0)}},
+			wantErr: "start must be non-negative and before end",
+		},
+		{
+			name:    "relabel an unknown speaker",
+			ops:     []domain.SegmentOperation{{Op: domain.OpRelabelSpeaker, From: "SPEAKER_09", Speaker: ptr("Budi")}},
+			wantErr: `no segments with speaker "SPEAKER_09"`,
+		},
+		{
+			name:    "relabel without a target",
+			ops:     []domain.SegmentOperation{{Op: domain.OpRelabelSpeaker, Speaker: ptr("Budi")}},
+			wantErr: "segment_id or from is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments := testSegments()
+
+			got, err := Apply(segments, tt.ops)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Apply error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Apply =\n%+v\nwant\n%+v", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(segments, testSegments()) {
+				t.Fatalf("Apply modified its input: %+v", segments)
+			}
+		})
+	}
+}
+
+func TestJoinText(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []domain.Segment
+		want     string
+	}{
+		{name: "none", want: ""},
+		{name: "segments", segments: testSegments(), want: "Selamat pagi semua. Mari kita mulai. Agenda pertama."},
+		{name: "blank segments are skipped", segments: []domain.Segment{{Text: " Halo "}, {Text: "  "}, {Text: "semua"}}, want: "Halo semua"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JoinText(tt.segments); got != tt.want {
+				t.Fatalf("JoinText = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
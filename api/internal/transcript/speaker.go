@@ -0,0 +1,14 @@
+package transcript
+
+import "transcribe/internal/domain"
+
+// RenameSpeakers replaces diarization labels with their mapped names in
+// place, keeping the original label in SpeakerLabel.
+func RenameSpeakers(segments []domain.Segment, names map[string]string) {
+	for i := range segments {
+		if name := names[segments[i].Speaker]; name != "" {
+			segments[i].SpeakerLabel = segments[i].Speaker
+			segments[i].Speaker = name
+		}
+	}
+}
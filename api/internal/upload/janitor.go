@@ -0,0 +1,76 @@
+package upload
+
+import (
+	"context"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+)
+
+const (
+	janitorInterval  = 10 * time.Minute
+	janitorBatchSize = 100
+)
+
+type Janitor struct {
+	uploadRepo *repository.UploadRepository
+	interval   time.Duration
+}
+
+func NewJanitor() *Janitor {
+	return &Janitor{
+		uploadRepo: repository.NewUploadRepository(),
+		interval:   janitorInterval,
+	}
+}
+
+func (j *Janitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	logger.Log.Infof("upload janitor started (interval %s)", j.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Log.Info("upload janitor stopped")
+			return
+		case <-ticker.C:
+			j.removeExpired(ctx)
+		}
+	}
+}
+
+func (j *Janitor) removeExpired(ctx context.Context) {
+	uploads, err := j.uploadRepo.FindExpired(time.Now(), janitorBatchSize)
+
+	if err != nil {
+		logger.Log.Errorf("failed to fetch expired uploads: %v", err)
+		return
+	}
+
+	for _, upload := range uploads {
+		log := logger.Log.WithField("upload_id", upload.ID)
+
+		chunks, err := j.uploadRepo.Chunks(upload.ID)
+
+		if err != nil {
+			log.Errorf("failed to fetch upload chunks: %v", err)
+			continue
+		}
+
+		for _, chunk := range chunks {
+			if err := config.Storage.Delete(ctx, chunk.StorageKey); err != nil {
+				log.Warnf("failed to delete upload chunk %s: %v", chunk.StorageKey, err)
+			}
+		}
+
+		if err := j.uploadRepo.Delete(upload.ID); err != nil {
+			log.Errorf("failed to delete expired upload: %v", err)
+			continue
+		}
+
+		log.Info("expired upload removed")
+	}
+}
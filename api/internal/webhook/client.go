@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrAddressNotAllowed = errors.New("webhook address is not a public address")
+	ErrRedirect          = errors.New("webhook endpoint redirected, redirects are not followed")
+)
+
+// blockedPrefixes are ranges that are not covered by the netip helpers but
+// must not be reachable from webhooks either.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// NewClient returns an HTTP client for webhook deliveries. Every connection,
+// including ones to addresses a hostname resolves to at delivery time, is
+// refused unless the peer is a public address, and redirects are not followed.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+
+			if err != nil {
+				return err
+			}
+
+			addr, err := netip.ParseAddr(host)
+
+			if err != nil || !IsPublicAddr(addr) {
+				return ErrAddressNotAllowed
+			}
+
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        20,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return ErrRedirect
+		},
+	}
+}
+
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckURL resolves the URL's host and rejects it when any of its addresses is
+// not public. It gives early feedback on registration; the client enforces
+// the same rule on every delivery.
+func CheckURL(ctx context.Context, u *url.URL) error {
+	host := u.Hostname()
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !IsPublicAddr(addr) {
+			return ErrAddressNotAllowed
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+
+	if err != nil || len(addrs) == 0 {
+		return errors.New("webhook host could not be resolved")
+	}
+
+	for _, addr := range addrs {
+		if !IsPublicAddr(addr) {
+			return ErrAddressNotAllowed
+		}
+	}
+
+	return nil
+}
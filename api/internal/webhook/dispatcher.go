@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+	"transcribe/internal/queue"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	batchSize = 20
+	userAgent = "transcribe-webhooks/1.0"
+)
+
+type Dispatcher struct {
+	webhookRepo    *repository.WebhookRepository
+	client         *http.Client
+	interval       time.Duration
+	maxAttempts    int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		webhookRepo:    repository.NewWebhookRepository(),
+		client:         NewClient(config.AppConfig.WebhookTimeout),
+		interval:       config.AppConfig.WebhookPollInterval,
+		maxAttempts:    config.AppConfig.WebhookMaxAttempts,
+		retryBaseDelay: config.AppConfig.WebhookRetryBaseDelay,
+		retryMaxDelay:  config.AppConfig.WebhookRetryMaxDelay,
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	logger.Log.Infof("webhook dispatcher started (interval %s)", d.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Log.Info("webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+			d.dispatch(ctx)
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) {
+	deliveries, err := d.webhookRepo.ClaimDue(batchSize, 2*d.client.Timeout)
+
+	if err != nil {
+		logger.Log.Errorf("failed to claim webhook deliveries: %v", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+
+	for i := range deliveries {
+		wg.Add(1)
+
+		go func(delivery *domain.WebhookDelivery) {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}(&deliveries[i])
+	}
+
+	wg.Wait()
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	log := logger.Log.WithFields(logrus.Fields{
+		"webhook_id":  delivery.WebhookID,
+		"delivery_id": delivery.ID,
+		"job_id":      delivery.JobID,
+	})
+
+	webhook, err := d.webhookRepo.FindByID(delivery.WebhookID)
+
+	if err != nil || !webhook.Active {
+		d.webhookRepo.UpdateDelivery(delivery.ID, map[string]interface{}{
+			"status":     domain.WebhookDeliveryFailed,
+			"last_error": "webhook is deleted or disabled",
+		})
+		return
+	}
+
+	attempts := delivery.Attempts + 1
+	updates := map[string]interface{}{"attempts": attempts}
+
+	status, err := d.post(ctx, webhook, delivery)
+
+	updates["response_status"] = status
+
+	if err == nil && status >= 200 && status < 300 {
+		updates["status"] = domain.WebhookDeliverySucceeded
+		updates["delivered_at"] = time.Now()
+		updates["last_error"] = ""
+
+		if err := d.webhookRepo.UpdateDelivery(delivery.ID, updates); err != nil {
+			log.Errorf("failed to record webhook delivery: %v", err)
+		}
+
+		log.Infof("webhook delivered (%s)", delivery.Event)
+		return
+	}
+
+	if err == nil {
+		err = fmt.Errorf("endpoint responded with status %d", status)
+	}
+
+	updates["last_error"] = err.Error()
+
+	if attempts >= d.maxAttempts {
+		updates["status"] = domain.WebhookDeliveryFailed
+		log.Warnf("webhook delivery failed permanently after %d attempts: %v", attempts, err)
+	} else {
+		delay := queue.Backoff(attempts, d.retryBaseDelay, d.retryMaxDelay)
+		updates["next_attempt_at"] = time.Now().Add(delay)
+		log.Warnf("webhook delivery failed, retrying in %s: %v", delay, err)
+	}
+
+	if err := d.webhookRepo.UpdateDelivery(delivery.ID, updates); err != nil {
+		log.Errorf("failed to record webhook delivery: %v", err)
+	}
+}
+
+// post sends the delivery and returns the response status. The response body
+// is discarded so the endpoint's reply never reaches the webhook owner.
+func (d *Dispatcher) post(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) (int, error) {
+	payload := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, payload))
+
+	resp, err := d.client.Do(req)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	// Drain a little so the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"transcribe/config"
+	"transcribe/internal/domain"
+	"transcribe/internal/progress"
+	"transcribe/internal/repository"
+	"transcribe/pkg/logger"
+)
+
+type Listener struct {
+	webhookRepo       *repository.WebhookRepository
+	transcriptionRepo *repository.TranscriptionRepository
+}
+
+func NewListener() *Listener {
+	return &Listener{
+		webhookRepo:       repository.NewWebhookRepository(),
+		transcriptionRepo: repository.NewTranscriptionRepository(),
+	}
+}
+
+func (l *Listener) Run(ctx context.Context) {
+	pubsub := config.RedisClient.PSubscribe(ctx, progress.ChannelPrefix+"*")
+	defer pubsub.Close()
+
+	logger.Log.Info("webhook listener started")
+
+	ch := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Log.Info("webhook listener stopped")
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			l.handle(msg.Payload)
+		}
+	}
+}
+
+func (l *Listener) handle(payload string) {
+	var msg progress.Message
+
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		logger.Log.Warnf("invalid progress message: %v", err)
+		return
+	}
+
+	event, ok := domain.WebhookEventForStatus(msg.Status)
+
+	if !ok {
+		return
+	}
+
+	log := logger.Log.WithField("job_id", msg.JobID)
+
+	job, err := l.transcriptionRepo.FindByID(msg.JobID)
+
+	if err != nil {
+		log.Warnf("failed to load job for webhook event: %v", err)
+		return
+	}
+
+	webhooks, err := l.webhookRepo.FindActiveByUserID(job.UserID)
+
+	if err != nil {
+		log.Errorf("failed to load webhooks: %v", err)
+		return
+	}
+
+	if len(webhooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(domain.WebhookPayload{
+		Event:     event,
+		CreatedAt: time.Now(),
+		Job: domain.WebhookJob{
+			JobID:       job.ID,
+			Status:      msg.Status,
+			FileName:    job.FileName,
+			Duration:    job.Duration,
+			ErrorMsg:    job.ErrorMsg,
+			CreatedAt:   job.CreatedAt,
+			CompletedAt: job.CompletedAt,
+		},
+	})
+
+	if err != nil {
+		log.Errorf("failed to encode webhook payload: %v", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !Subscribed(&webhook, event) {
+			continue
+		}
+
+		created, err := l.webhookRepo.CreateDelivery(&domain.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			JobID:         job.ID,
+			Event:         event,
+			DedupeKey:     dedupeKey(webhook.ID, job.ID, msg.EventID),
+			Payload:       string(body),
+			Status:        domain.WebhookDeliveryPending,
+			NextAttemptAt: time.Now(),
+		})
+
+		if err != nil {
+			log.Errorf("failed to create webhook delivery: %v", err)
+			continue
+		}
+
+		if created {
+			log.WithField("webhook_id", webhook.ID).Debugf("webhook delivery scheduled for %s", event)
+		}
+	}
+}
+
+// dedupeKey identifies the delivery of one progress event to one webhook, so
+// every API instance receiving the same message schedules it only once. The
+// event id is the job stream entry, which is unique for every status change,
+// including ones repeated after a dead job is replayed.
+func dedupeKey(webhookID uint, jobID, eventID string) *string {
+	if eventID == "" {
+		return nil
+	}
+
+	key := fmt.Sprintf("%d:%s:%s", webhookID, jobID, eventID)
+
+	return &key
+}
+
+func Subscribed(webhook *domain.Webhook, event string) bool {
+	for _, e := range strings.Split(webhook.Events, ",") {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+package helpers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+	"transcribe/config"
+)
+
+const denylistPrefix = "token_denylist:"
+
+func GenerateRefreshToken() (string, string, error) {
+	buf := make([]byte, 32)
+
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	return token, HashToken(token), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+
+	if jti == "" || ttl <= 0 {
+		return nil
+	}
+
+	return config.RedisClient.Set(ctx, denylistPrefix+jti, "1", ttl).Err()
+}
+
+func IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := config.RedisClient.Exists(ctx, denylistPrefix+jti).Result()
+
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+func GenerateAPIKey(prefix string) (string, string, error) {
+	buf := make([]byte, 24)
+
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	key := prefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	return key, HashToken(key), nil
+}
@@ -0,0 +1,95 @@
+package media
+
+import (
+	"bytes"
+	"io"
+)
+
+const aviMaxHeaderList = 1024 * 1024
+
+func inspectAVI(r io.ReaderAt, size int64) (*Info, error) {
+	header, err := readAt(r, 12, 12, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(header[0:4], []byte("LIST")) || !bytes.Equal(header[8:12], []byte("hdrl")) {
+		return nil, corrupt("avi hdrl list not found")
+	}
+
+	listSize := u32le(header[4:8]) - 4
+
+	if listSize <= 0 || listSize > aviMaxHeaderList {
+		return nil, corrupt("avi hdrl list has invalid size")
+	}
+
+	hdrl, err := readAt(r, 24, listSize, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{}
+
+	for _, chunk := range riffChunks(hdrl) {
+		switch {
+		case chunk.kind == "avih" && len(chunk.body) >= 20:
+			microSecPerFrame := u32le(chunk.body[0:4])
+			totalFrames := u32le(chunk.body[16:20])
+			info.Duration = float64(microSecPerFrame*totalFrames) / 1e6
+
+		case chunk.kind == "LIST" && len(chunk.body) >= 4 && string(chunk.body[0:4]) == "strl":
+			audioStream(riffChunks(chunk.body[4:]), info)
+		}
+	}
+
+	return info, nil
+}
+
+func audioStream(chunks []box, info *Info) {
+	strh := find(chunks, "strh")
+
+	if strh == nil || len(strh.body) < 36 || string(strh.body[0:4]) != "auds" {
+		return
+	}
+
+	if strf := find(chunks, "strf"); strf != nil && len(strf.body) >= 8 && info.Channels == 0 {
+		info.Channels = u16le(strf.body[2:4])
+		info.SampleRate = int(u32le(strf.body[4:8]))
+	}
+
+	if info.Duration > 0 {
+		return
+	}
+
+	scale, rate, length := u32le(strh.body[20:24]), u32le(strh.body[24:28]), u32le(strh.body[32:36])
+
+	if rate > 0 {
+		info.Duration = float64(length*scale) / float64(rate)
+	}
+}
+
+func riffChunks(data []byte) []box {
+	var chunks []box
+
+	for len(data) >= 8 {
+		chunkSize := u32le(data[4:8])
+
+		if chunkSize > int64(len(data)-8) {
+			break
+		}
+
+		chunks = append(chunks, box{kind: string(data[0:4]), body: data[8 : 8+chunkSize]})
+
+		next := 8 + chunkSize + chunkSize%2
+
+		if next > int64(len(data)) {
+			break
+		}
+
+		data = data[next:]
+	}
+
+	return chunks
+}
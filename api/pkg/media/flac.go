@@ -0,0 +1,36 @@
+package media
+
+import (
+	"bytes"
+	"io"
+)
+
+func inspectFLAC(r io.ReaderAt, start, size int64) (*Info, error) {
+	header, err := readAt(r, start, 8+34, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(header[0:4], []byte("fLaC")) || header[4]&0x7F != 0 {
+		return nil, corrupt("flac STREAMINFO block not found")
+	}
+
+	streamInfo := header[8:]
+
+	sampleRate := int(streamInfo[10])<<12 | int(streamInfo[11])<<4 | int(streamInfo[12])>>4
+	channels := int(streamInfo[12]>>1&0x07) + 1
+	totalSamples := int64(streamInfo[13]&0x0F)<<32 | u32be(streamInfo[14:18])
+
+	if sampleRate == 0 {
+		return nil, corrupt("flac sample rate is zero")
+	}
+
+	// totalSamples is zero when the encoder did not know the length; Duration
+	// stays zero then.
+	return &Info{
+		Duration:   float64(totalSamples) / float64(sampleRate),
+		SampleRate: sampleRate,
+		Channels:   channels,
+	}, nil
+}
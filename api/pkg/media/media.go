@@ -0,0 +1,165 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type Format string
+
+const (
+	FormatWAV  Format = "wav"
+	FormatMP3  Format = "mp3"
+	FormatOgg  Format = "ogg"
+	FormatFLAC Format = "flac"
+	FormatMP4  Format = "mp4"
+	FormatAVI  Format = "avi"
+)
+
+const SniffLength = 16
+
+var (
+	ErrUnsupported = errors.New("unsupported media format")
+	ErrCorrupt     = errors.New("corrupt media file")
+)
+
+type Info struct {
+	Format     Format  `json:"format"`
+	Duration   float64 `json:"duration"`
+	SampleRate int     `json:"sample_rate,omitempty"`
+	Channels   int     `json:"channels,omitempty"`
+}
+
+var mp4Boxes = [][]byte{
+	[]byte("ftyp"), []byte("moov"), []byte("mdat"), []byte("wide"), []byte("free"), []byte("skip"),
+}
+
+func Detect(header []byte) (Format, error) {
+	if len(header) < 12 {
+		return "", ErrUnsupported
+	}
+
+	switch {
+	case bytes.Equal(header[0:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WAVE")):
+		return FormatWAV, nil
+	case bytes.Equal(header[0:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("AVI ")):
+		return FormatAVI, nil
+	case bytes.Equal(header[0:4], []byte("OggS")):
+		return FormatOgg, nil
+	case bytes.Equal(header[0:4], []byte("fLaC")):
+		return FormatFLAC, nil
+	case bytes.Equal(header[0:3], []byte("ID3")):
+		return FormatMP3, nil
+	case header[0] == 0xFF && header[1]&0xE0 == 0xE0:
+		if _, err := parseFrameHeader(header); err == nil {
+			return FormatMP3, nil
+		}
+	}
+
+	for _, box := range mp4Boxes {
+		if bytes.Equal(header[4:8], box) {
+			return FormatMP4, nil
+		}
+	}
+
+	return "", ErrUnsupported
+}
+
+func Inspect(r io.ReaderAt, size int64) (*Info, error) {
+	start, err := skipID3(r, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := readAt(r, start, SniffLength, size)
+
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+
+	format, err := Detect(header)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var info *Info
+
+	switch format {
+	case FormatWAV:
+		info, err = inspectWAV(r, size)
+	case FormatAVI:
+		info, err = inspectAVI(r, size)
+	case FormatOgg:
+		info, err = inspectOgg(r, size)
+	case FormatFLAC:
+		info, err = inspectFLAC(r, start, size)
+	case FormatMP3:
+		info, err = inspectMPEG(r, start, size)
+	case FormatMP4:
+		info, err = inspectMP4(r, size)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// A FLAC encoder that streams its output may leave total_samples at zero;
+	// the duration is then unknown, the file itself is fine.
+	if info.Duration <= 0 && format != FormatFLAC {
+		return nil, corrupt("unable to determine %s duration", format)
+	}
+
+	info.Format = format
+
+	return info, nil
+}
+
+func corrupt(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrCorrupt, fmt.Sprintf(format, args...))
+}
+
+func readAt(r io.ReaderAt, offset, n, size int64) ([]byte, error) {
+	if offset < 0 || n < 0 || offset+n > size {
+		return nil, corrupt("unexpected end of file")
+	}
+
+	buf := make([]byte, n)
+
+	if _, err := r.ReadAt(buf, offset); err != nil && !(errors.Is(err, io.EOF) && offset+n == size) {
+		return nil, corrupt("read failed: %v", err)
+	}
+
+	return buf, nil
+}
+
+func skipID3(r io.ReaderAt, size int64) (int64, error) {
+	var offset int64
+
+	for {
+		header, err := readAt(r, offset, 10, size)
+
+		if err != nil || !bytes.Equal(header[0:3], []byte("ID3")) {
+			return offset, nil
+		}
+
+		tagSize := int64(header[6]&0x7F)<<21 | int64(header[7]&0x7F)<<14 | int64(header[8]&0x7F)<<7 | int64(header[9]&0x7F)
+		offset += 10 + tagSize
+
+		if header[5]&0x10 != 0 {
+			offset += 10
+		}
+
+		if offset >= size {
+			return 0, corrupt("ID3 tag exceeds file size")
+		}
+	}
+}
+
+func u16le(b []byte) int   { return int(binary.LittleEndian.Uint16(b)) }
+func u32le(b []byte) int64 { return int64(binary.LittleEndian.Uint32(b)) }
+func u32be(b []byte) int64 { return int64(binary.BigEndian.Uint32(b)) }
@@ -0,0 +1,330 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+)
+
+func wavFile(sampleRate, channels, dataLen int) []byte {
+	byteRate := sampleRate * channels * 2
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(36+dataLen))
+	b.WriteString("WAVEfmt ")
+	binary.Write(&b, binary.LittleEndian, uint32(16))
+	binary.Write(&b, binary.LittleEndian, uint16(1))
+	binary.Write(&b, binary.LittleEndian, uint16(channels))
+	binary.Write(&b, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&b, binary.LittleEndian, uint32(byteRate))
+	binary.Write(&b, binary.LittleEndian, uint16(channels*2))
+	binary.Write(&b, binary.LittleEndian, uint16(16))
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, uint32(dataLen))
+	b.Write(make([]byte, dataLen))
+
+	return b.Bytes()
+}
+
+func flacFile(sampleRate, channels int, totalSamples int64) []byte {
+	streamInfo := make([]byte, 34)
+	streamInfo[10] = byte(sampleRate >> 12)
+	streamInfo[11] = byte(sampleRate >> 4)
+	streamInfo[12] = byte(sampleRate&0x0F)<<4 | byte(channels-1)<<1
+	streamInfo[13] = 15<<4 | byte(totalSamples>>32&0x0F)
+	binary.BigEndian.PutUint32(streamInfo[14:18], uint32(totalSamples))
+
+	b := append([]byte("fLaC"), 0x80, 0, 0, 34)
+
+	return append(append(b, streamInfo...), make([]byte, 64)...)
+}
+
+func oggPage(granule int64, packet []byte) []byte {
+	page := []byte("OggS\x00\x02")
+	page = binary.LittleEndian.AppendUint64(page, uint64(granule))
+	page = append(page, 1, 2, 3, 4)
+	page = append(page, make([]byte, 8)...)
+
+	if packet == nil {
+		return append(page, 0)
+	}
+
+	page = append(page, 1, byte(len(packet)))
+
+	return append(page, packet...)
+}
+
+func opusFile(channels, preSkip int, granule int64) []byte {
+	head := []byte("OpusHead\x01")
+	head = append(head, byte(channels))
+	head = binary.LittleEndian.AppendUint16(head, uint16(preSkip))
+	head = binary.LittleEndian.AppendUint32(head, 44100)
+	head = append(head, 0, 0, 0)
+
+	return append(oggPage(0, head), oggPage(granule, nil)...)
+}
+
+func vorbisFile(sampleRate, channels int, granule int64) []byte {
+	head := []byte("\x01vorbis")
+	head = append(head, 0, 0, 0, 0, byte(channels))
+	head = binary.LittleEndian.AppendUint32(head, uint32(sampleRate))
+	head = append(head, make([]byte, 14)...)
+
+	return append(oggPage(0, head), oggPage(granule, nil)...)
+}
+
+// mp3Frames returns frames of MPEG-1 Layer III at 128 kbps and 44.1 kHz,
+// 417 bytes each. fill is copied after each frame header.
+func mp3Frames(n int, mono bool, fill func(frame []byte)) []byte {
+	var b []byte
+
+	for i := 0; i < n; i++ {
+		frame := make([]byte, 417)
+		copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+
+		if mono {
+			frame[3] = 0xC0
+		}
+
+		if fill != nil && i == 0 {
+			fill(frame)
+		}
+
+		b = append(b, frame...)
+	}
+
+	return b
+}
+
+func id3Tag(size int) []byte {
+	tag := []byte{'I', 'D', '3', 3, 0, 0, byte(size >> 21 & 0x7F), byte(size >> 14 & 0x7F), byte(size >> 7 & 0x7F), byte(size & 0x7F)}
+
+	return append(tag, make([]byte, size)...)
+}
+
+func mp4Box(kind string, body ...[]byte) []byte {
+	content := bytes.Join(body, nil)
+	b := binary.BigEndian.AppendUint32(nil, uint32(8+len(content)))
+	b = append(b, kind...)
+
+	return append(b, content...)
+}
+
+func mp4File(timescale, duration uint32, channels, sampleRate uint16) []byte {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:16], timescale)
+	binary.BigEndian.PutUint32(mvhd[16:20], duration)
+
+	hdlr := make([]byte, 24)
+	copy(hdlr[8:12], "soun")
+
+	stsd := make([]byte, 8+36)
+	binary.BigEndian.PutUint16(stsd[8+24:8+26], channels)
+	binary.BigEndian.PutUint16(stsd[8+32:8+34], sampleRate)
+
+	trak := mp4Box("trak", mp4Box("mdia", mp4Box("hdlr", hdlr), mp4Box("minf", mp4Box("stbl", mp4Box("stsd", stsd)))))
+
+	return append(mp4Box("ftyp", []byte("isom\x00\x00\x02\x00")), mp4Box("moov", mp4Box("mvhd", mvhd), trak)...)
+}
+
+func riffChunk(kind string, body []byte) []byte {
+	b := append([]byte(kind), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...)
+
+	return append(b, body...)
+}
+
+func aviFile(microSecPerFrame, totalFrames uint32, channels uint16, sampleRate uint32) []byte {
+	avih := make([]byte, 56)
+	binary.LittleEndian.PutUint32(avih[0:4], microSecPerFrame)
+	binary.LittleEndian.PutUint32(avih[16:20], totalFrames)
+
+	strh := make([]byte, 56)
+	copy(strh, "auds")
+
+	strf := make([]byte, 16)
+	binary.LittleEndian.PutUint16(strf[2:4], channels)
+	binary.LittleEndian.PutUint32(strf[4:8], sampleRate)
+
+	strl := append([]byte("strl"), append(riffChunk("strh", strh), riffChunk("strf", strf)...)...)
+	hdrl := append([]byte("hdrl"), append(riffChunk("avih", avih), riffChunk("LIST", strl)...)...)
+	body := append([]byte("AVI "), riffChunk("LIST", hdrl)...)
+
+	return riffChunk("RIFF", body)
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  []byte
+		want    Format
+		wantErr error
+	}{
+		{name: "wav", header: wavFile(16000, 1, 0), want: FormatWAV},
+		{name: "avi", header: aviFile(40000, 1, 2, 44100), want: FormatAVI},
+		{name: "ogg", header: opusFile(2, 0, 1), want: FormatOgg},
+		{name: "flac", header: flacFile(44100, 2, 1), want: FormatFLAC},
+		{name: "id3", header: id3Tag(4), want: FormatMP3},
+		{name: "mpeg frame", header: mp3Frames(1, false, nil), want: FormatMP3},
+		{name: "mp4", header: mp4File(1000, 1000, 2, 44100), want: FormatMP4},
+		{name: "text", header: []byte("just some plain text"), wantErr: ErrUnsupported},
+		{name: "frame sync with bad header", header: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0, 0, 0, 0, 0}, wantErr: ErrUnsupported},
+		{name: "too short", header: []byte("RIFF"), wantErr: ErrUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Detect(tt.header)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Detect error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("Detect = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInspect(t *testing.T) {
+	xing := func(frame []byte) {
+		copy(frame[21:], "Xing")
+		binary.BigEndian.PutUint32(frame[25:29], 1)
+		binary.BigEndian.PutUint32(frame[29:33], 100)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    Info
+		wantErr error
+	}{
+		{
+			name: "wav",
+			data: wavFile(16000, 1, 64000),
+			want: Info{Format: FormatWAV, Duration: 2, SampleRate: 16000, Channels: 1},
+		},
+		{
+			name: "wav with truncated data chunk",
+			data: wavFile(16000, 1, 64000)[:44+32000],
+			want: Info{Format: FormatWAV, Duration: 1, SampleRate: 16000, Channels: 1},
+		},
+		{
+			name: "flac",
+			data: flacFile(48000, 2, 144000),
+			want: Info{Format: FormatFLAC, Duration: 3, SampleRate: 48000, Channels: 2},
+		},
+		{
+			name: "flac without sample count",
+			data: flacFile(44100, 1, 0),
+			want: Info{Format: FormatFLAC, SampleRate: 44100, Channels: 1},
+		},
+		{
+			name: "opus",
+			data: opusFile(2, 312, 96312),
+			want: Info{Format: FormatOgg, Duration: 2, SampleRate: 44100, Channels: 2},
+		},
+		{
+			name: "vorbis",
+			data: vorbisFile(22050, 1, 66150),
+			want: Info{Format: FormatOgg, Duration: 3, SampleRate: 22050, Channels: 1},
+		},
+		{
+			name: "mp3 constant bitrate",
+			data: mp3Frames(10, false, nil),
+			want: Info{Format: FormatMP3, Duration: 4170 * 8 / 128000.0, SampleRate: 44100, Channels: 2},
+		},
+		{
+			name: "mp3 after id3 tag",
+			data: append(id3Tag(100), mp3Frames(10, false, nil)...),
+			want: Info{Format: FormatMP3, Duration: 4170 * 8 / 128000.0, SampleRate: 44100, Channels: 2},
+		},
+		{
+			name: "mp3 with xing frame count",
+			data: mp3Frames(3, true, xing),
+			want: Info{Format: FormatMP3, Duration: 100 * 1152 / 44100.0, SampleRate: 44100, Channels: 1},
+		},
+		{
+			name: "mp4",
+			data: mp4File(1000, 12500, 2, 44100),
+			want: Info{Format: FormatMP4, Duration: 12.5, SampleRate: 44100, Channels: 2},
+		},
+		{
+			name: "avi",
+			data: aviFile(40000, 250, 2, 44100),
+			want: Info{Format: FormatAVI, Duration: 10, SampleRate: 44100, Channels: 2},
+		},
+		{
+			name:    "unsupported",
+			data:    []byte("this is not an audio file at all"),
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "wav without data chunk",
+			data:    wavFile(16000, 1, 0)[:36],
+			wantErr: ErrCorrupt,
+		},
+		{
+			name:    "wav with empty data chunk",
+			data:    wavFile(16000, 1, 0),
+			wantErr: ErrCorrupt,
+		},
+		{
+			name:    "flac truncated",
+			data:    flacFile(44100, 2, 1)[:20],
+			wantErr: ErrCorrupt,
+		},
+		{
+			name:    "flac with zero sample rate",
+			data:    flacFile(0, 2, 1000),
+			wantErr: ErrCorrupt,
+		},
+		{
+			name:    "ogg with unknown codec",
+			data:    append(oggPage(0, []byte("\x80theora-not-audio")), oggPage(10, nil)...),
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "ogg without end granule",
+			data:    opusFile(2, 0, 0),
+			wantErr: ErrCorrupt,
+		},
+		{
+			name:    "mp4 without moov",
+			data:    mp4Box("ftyp", []byte("isom\x00\x00\x02\x00"), make([]byte, 16)),
+			wantErr: ErrCorrupt,
+		},
+		{
+			name:    "mp4 with zero duration",
+			data:    mp4File(1000, 0, 2, 44100),
+			wantErr: ErrCorrupt,
+		},
+		{
+			name:    "id3 tag larger than the file",
+			data:    id3Tag(100)[:50],
+			wantErr: ErrCorrupt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Inspect(bytes.NewReader(tt.data), int64(len(tt.data)))
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Inspect error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.Format != tt.want.Format || got.SampleRate != tt.want.SampleRate || got.Channels != tt.want.Channels ||
+				math.Abs(got.Duration-tt.want.Duration) > 1e-6 {
+				t.Fatalf("Inspect = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
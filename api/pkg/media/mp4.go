@@ -0,0 +1,182 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+const mp4MaxMovieBox = 64 * 1024 * 1024
+
+type box struct {
+	kind string
+	body []byte
+}
+
+func inspectMP4(r io.ReaderAt, size int64) (*Info, error) {
+	var offset int64
+
+	for offset+8 <= size {
+		header, err := readAt(r, offset, 16, size)
+
+		if err != nil {
+			header, err = readAt(r, offset, 8, size)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		boxSize, headerSize := u32be(header[0:4]), int64(8)
+
+		switch boxSize {
+		case 0:
+			boxSize = size - offset
+		case 1:
+			if len(header) < 16 {
+				return nil, corrupt("mp4 box header truncated")
+			}
+
+			boxSize, headerSize = int64(binary.BigEndian.Uint64(header[8:16])), 16
+		}
+
+		if boxSize < headerSize || offset+boxSize > size {
+			return nil, corrupt("mp4 box exceeds file size")
+		}
+
+		if bytes.Equal(header[4:8], []byte("moov")) {
+			if boxSize > mp4MaxMovieBox {
+				return nil, corrupt("mp4 moov box too large")
+			}
+
+			body, err := readAt(r, offset+headerSize, boxSize-headerSize, size)
+
+			if err != nil {
+				return nil, err
+			}
+
+			return parseMovie(body)
+		}
+
+		offset += boxSize
+	}
+
+	return nil, corrupt("mp4 moov box not found")
+}
+
+func parseMovie(moov []byte) (*Info, error) {
+	info := &Info{}
+
+	for _, b := range children(moov) {
+		switch b.kind {
+		case "mvhd":
+			duration, err := movieDuration(b.body)
+
+			if err != nil {
+				return nil, err
+			}
+
+			info.Duration = duration
+		case "trak":
+			if info.Channels == 0 {
+				info.Channels, info.SampleRate = soundTrack(b.body)
+			}
+		}
+	}
+
+	return info, nil
+}
+
+func movieDuration(mvhd []byte) (float64, error) {
+	var timescale, duration int64
+
+	switch {
+	case len(mvhd) >= 20 && mvhd[0] == 0:
+		timescale = u32be(mvhd[12:16])
+		duration = u32be(mvhd[16:20])
+	case len(mvhd) >= 32 && mvhd[0] == 1:
+		timescale = u32be(mvhd[20:24])
+		duration = int64(binary.BigEndian.Uint64(mvhd[24:32]))
+	default:
+		return 0, corrupt("mp4 mvhd box truncated")
+	}
+
+	if timescale == 0 {
+		return 0, corrupt("mp4 timescale is zero")
+	}
+
+	return float64(duration) / float64(timescale), nil
+}
+
+func soundTrack(trak []byte) (int, int) {
+	mdia := find(children(trak), "mdia")
+
+	if mdia == nil {
+		return 0, 0
+	}
+
+	boxes := children(mdia.body)
+
+	if hdlr := find(boxes, "hdlr"); hdlr == nil || len(hdlr.body) < 12 || string(hdlr.body[8:12]) != "soun" {
+		return 0, 0
+	}
+
+	stsd := path(boxes, "minf", "stbl", "stsd")
+
+	if stsd == nil || len(stsd.body) < 8+36 {
+		return 0, 0
+	}
+
+	entry := stsd.body[8:]
+
+	return int(binary.BigEndian.Uint16(entry[24:26])), int(binary.BigEndian.Uint16(entry[32:34]))
+}
+
+func path(boxes []box, kinds ...string) *box {
+	var current *box
+
+	for _, kind := range kinds {
+		current = find(boxes, kind)
+
+		if current == nil {
+			return nil
+		}
+
+		boxes = children(current.body)
+	}
+
+	return current
+}
+
+func find(boxes []box, kind string) *box {
+	for i := range boxes {
+		if boxes[i].kind == kind {
+			return &boxes[i]
+		}
+	}
+
+	return nil
+}
+
+func children(data []byte) []box {
+	var boxes []box
+
+	for len(data) >= 8 {
+		boxSize, headerSize := u32be(data[0:4]), int64(8)
+
+		if boxSize == 1 && len(data) >= 16 {
+			boxSize, headerSize = int64(binary.BigEndian.Uint64(data[8:16])), 16
+		} else if boxSize == 0 {
+			boxSize = int64(len(data))
+		}
+
+		if boxSize < headerSize || boxSize > int64(len(data)) {
+			break
+		}
+
+		boxes = append(boxes, box{kind: string(data[4:8]), body: data[headerSize:boxSize]})
+		data = data[boxSize:]
+	}
+
+	return boxes
+}
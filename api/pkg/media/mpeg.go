@@ -0,0 +1,177 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+const mpegSearchWindow = 64 * 1024
+
+var mpegBitrates = [5][16]int{
+	{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, 0},
+	{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 0},
+	{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+	{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0},
+	{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+}
+
+var mpegSampleRates = [3]int{44100, 48000, 32000}
+
+type frameHeader struct {
+	mpeg1           bool
+	layer           int
+	bitrate         int
+	sampleRate      int
+	channels        int
+	samplesPerFrame int
+	length          int
+}
+
+func parseFrameHeader(b []byte) (*frameHeader, error) {
+	if len(b) < 4 || b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return nil, errors.New("no frame sync")
+	}
+
+	version := b[1] >> 3 & 0x03
+	layerBits := b[1] >> 1 & 0x03
+	bitrateIndex := b[2] >> 4
+	rateIndex := b[2] >> 2 & 0x03
+	padding := int(b[2] >> 1 & 0x01)
+
+	if version == 1 || layerBits == 0 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return nil, errors.New("invalid frame header")
+	}
+
+	h := &frameHeader{
+		mpeg1:    version == 3,
+		layer:    4 - int(layerBits),
+		channels: 2,
+	}
+
+	if b[3]>>6 == 3 {
+		h.channels = 1
+	}
+
+	h.sampleRate = mpegSampleRates[rateIndex]
+
+	switch version {
+	case 2:
+		h.sampleRate /= 2
+	case 0:
+		h.sampleRate /= 4
+	}
+
+	var table int
+
+	switch {
+	case h.mpeg1:
+		table = h.layer - 1
+	case h.layer == 1:
+		table = 3
+	default:
+		table = 4
+	}
+
+	h.bitrate = mpegBitrates[table][bitrateIndex] * 1000
+
+	switch {
+	case h.layer == 1:
+		h.samplesPerFrame = 384
+		h.length = (12*h.bitrate/h.sampleRate + padding) * 4
+	case h.layer == 3 && !h.mpeg1:
+		h.samplesPerFrame = 576
+		h.length = 72*h.bitrate/h.sampleRate + padding
+	default:
+		h.samplesPerFrame = 1152
+		h.length = 144*h.bitrate/h.sampleRate + padding
+	}
+
+	return h, nil
+}
+
+func inspectMPEG(r io.ReaderAt, start, size int64) (*Info, error) {
+	window := int64(mpegSearchWindow)
+
+	if start+window > size {
+		window = size - start
+	}
+
+	buf, err := readAt(r, start, window, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i+4 <= len(buf); i++ {
+		header, err := parseFrameHeader(buf[i:])
+
+		if err != nil {
+			continue
+		}
+
+		next := i + header.length
+
+		if next+4 <= len(buf) {
+			if _, err := parseFrameHeader(buf[next:]); err != nil {
+				continue
+			}
+		} else if start+int64(next) != size {
+			continue
+		}
+
+		info := &Info{
+			SampleRate: header.sampleRate,
+			Channels:   header.channels,
+		}
+
+		if frames := vbrFrameCount(buf[i:], header); frames > 0 {
+			info.Duration = float64(frames*int64(header.samplesPerFrame)) / float64(header.sampleRate)
+
+			return info, nil
+		}
+
+		audioSize := size - start - int64(i)
+
+		if tag, err := readAt(r, size-128, 3, size); err == nil && bytes.Equal(tag, []byte("TAG")) {
+			audioSize -= 128
+		}
+
+		info.Duration = float64(audioSize*8) / float64(header.bitrate)
+
+		return info, nil
+	}
+
+	return nil, corrupt("no valid mpeg audio frame found")
+}
+
+func vbrFrameCount(frame []byte, h *frameHeader) int64 {
+	sideInfo := 32
+
+	switch {
+	case h.mpeg1 && h.channels == 1:
+		sideInfo = 17
+	case !h.mpeg1 && h.channels == 2:
+		sideInfo = 17
+	case !h.mpeg1:
+		sideInfo = 9
+	}
+
+	if xing := 4 + sideInfo; len(frame) >= xing+12 {
+		tag := frame[xing : xing+4]
+
+		if bytes.Equal(tag, []byte("Xing")) || bytes.Equal(tag, []byte("Info")) {
+			if u32be(frame[xing+4:xing+8])&0x01 != 0 {
+				return u32be(frame[xing+8 : xing+12])
+			}
+
+			return 0
+		}
+	}
+
+	if vbri := 4 + 32; len(frame) >= vbri+18 && bytes.Equal(frame[vbri:vbri+4], []byte("VBRI")) {
+		return u32be(frame[vbri+14 : vbri+18])
+	}
+
+	return 0
+}
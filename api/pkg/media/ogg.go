@@ -0,0 +1,102 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+const oggMaxPageSize = 27 + 255 + 255*255
+
+func inspectOgg(r io.ReaderAt, size int64) (*Info, error) {
+	header, err := readAt(r, 0, 27, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	serial := header[14:18]
+	segments := int64(header[26])
+
+	table, err := readAt(r, 27, segments, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var packetSize int64
+
+	for _, s := range table {
+		packetSize += int64(s)
+
+		if s < 255 {
+			break
+		}
+	}
+
+	packet, err := readAt(r, 27+segments, packetSize, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{}
+
+	var rate, preSkip int64
+
+	switch {
+	case len(packet) >= 16 && bytes.Equal(packet[0:7], []byte("\x01vorbis")):
+		info.Channels = int(packet[11])
+		info.SampleRate = int(u32le(packet[12:16]))
+		rate = int64(info.SampleRate)
+	case len(packet) >= 16 && bytes.Equal(packet[0:8], []byte("OpusHead")):
+		info.Channels = int(packet[9])
+		info.SampleRate = int(u32le(packet[12:16]))
+		preSkip = int64(u16le(packet[10:12]))
+		rate = 48000
+	default:
+		return nil, ErrUnsupported
+	}
+
+	if rate == 0 {
+		return nil, corrupt("ogg sample rate is zero")
+	}
+
+	granule, err := lastGranule(r, size, serial)
+
+	if err != nil {
+		return nil, err
+	}
+
+	info.Duration = float64(granule-preSkip) / float64(rate)
+
+	return info, nil
+}
+
+func lastGranule(r io.ReaderAt, size int64, serial []byte) (int64, error) {
+	window := int64(oggMaxPageSize)
+
+	if window > size {
+		window = size
+	}
+
+	tail, err := readAt(r, size-window, window, size)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for i := bytes.LastIndex(tail, []byte("OggS")); i >= 0; i = bytes.LastIndex(tail[:i], []byte("OggS")) {
+		if i+27 > len(tail) || !bytes.Equal(tail[i+14:i+18], serial) {
+			continue
+		}
+
+		granule := int64(binary.LittleEndian.Uint64(tail[i+6 : i+14]))
+
+		if granule > 0 {
+			return granule, nil
+		}
+	}
+
+	return 0, corrupt("ogg end of stream not found")
+}
@@ -0,0 +1,58 @@
+package media
+
+import (
+	"bytes"
+	"io"
+)
+
+func inspectWAV(r io.ReaderAt, size int64) (*Info, error) {
+	info := &Info{}
+
+	var byteRate int64
+	offset := int64(12)
+
+	for offset+8 <= size {
+		header, err := readAt(r, offset, 8, size)
+
+		if err != nil {
+			return nil, err
+		}
+
+		chunkSize := u32le(header[4:8])
+		body := offset + 8
+
+		switch {
+		case bytes.Equal(header[0:4], []byte("fmt ")):
+			if chunkSize < 16 {
+				return nil, corrupt("wav fmt chunk too short")
+			}
+
+			fmtChunk, err := readAt(r, body, 16, size)
+
+			if err != nil {
+				return nil, err
+			}
+
+			info.Channels = u16le(fmtChunk[2:4])
+			info.SampleRate = int(u32le(fmtChunk[4:8]))
+			byteRate = u32le(fmtChunk[8:12])
+
+		case bytes.Equal(header[0:4], []byte("data")):
+			if byteRate == 0 {
+				return nil, corrupt("wav data chunk before fmt chunk")
+			}
+
+			if body+chunkSize > size {
+				chunkSize = size - body
+			}
+
+			info.Duration = float64(chunkSize) / float64(byteRate)
+
+			return info, nil
+		}
+
+		offset = body + chunkSize + chunkSize%2
+	}
+
+	return nil, corrupt("wav data chunk not found")
+}
@@ -2,17 +2,36 @@ package middleware
 
 import (
 	"strings"
+	"time"
+	"transcribe/internal/repository"
 	"transcribe/pkg/helpers"
 	"transcribe/pkg/logger"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
 )
 
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+)
+
+var (
+	apiKeyRepo = repository.NewAPIKeyRepository()
+	userRepo   = repository.NewUserRepository()
+)
+
+const lastUsedResolution = time.Minute
+
 func AuthMiddleware(c *fiber.Ctx) error {
 	authHeader := c.Get("Authorization")
 
 	log := logger.Log.WithField("request_ip", c.IP())
 
+	if apiKey := c.Get("X-API-Key"); apiKey != "" {
+		return apiKeyAuth(c, apiKey, log)
+	}
+
 	if authHeader == "" {
 		tokenS := c.Query("token")
 		if tokenS != "" {
@@ -48,8 +67,82 @@ func AuthMiddleware(c *fiber.Ctx) error {
 		})
 	}
 
+	revoked, err := helpers.IsAccessTokenRevoked(c.Context(), claims.ID)
+
+	if err != nil {
+		log.Errorf("failed to check token revocation: %v", err)
+
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "unable to verify token",
+		})
+	}
+
+	if revoked {
+		log.Warn("revoked token used")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "invalid or expired token",
+		})
+	}
+
 	c.Locals("user_id", claims.UserID)
 	c.Locals("email", claims.Email)
+	c.Locals("role", claims.Role)
+	c.Locals("auth_method", AuthMethodJWT)
+	c.Locals("token_id", claims.ID)
+	c.Locals("token_expires_at", claims.ExpiresAt.Time)
+
+	return c.Next()
+}
+
+func apiKeyAuth(c *fiber.Ctx, rawKey string, log *logrus.Entry) error {
+	key, err := apiKeyRepo.FindByHash(helpers.HashToken(rawKey))
+
+	if err != nil {
+		log.Warnf("invalid api key: %v", err)
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "invalid or expired api key",
+		})
+	}
+
+	log = log.WithFields(logrus.Fields{
+		"user_id":    key.UserID,
+		"api_key_id": key.ID,
+	})
+
+	now := time.Now()
+
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		log.Warn("revoked or expired api key used")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "invalid or expired api key",
+		})
+	}
+
+	user, err := userRepo.FindByID(key.UserID)
+
+	if err != nil || user.DisabledAt != nil {
+		log.Warnf("api key owner not found or disabled: %v", err)
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "invalid or expired api key",
+		})
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := apiKeyRepo.TouchLastUsed(key.ID, now); err != nil {
+			log.Warnf("failed to record api key usage: %v", err)
+		}
+	}
+
+	c.Locals("user_id", user.ID)
+	c.Locals("email", user.Email)
+	c.Locals("role", user.Role)
+	c.Locals("auth_method", AuthMethodAPIKey)
+	c.Locals("api_key_id", key.ID)
+	c.Locals("scopes", strings.Split(key.Scopes, ","))
 
 	return c.Next()
 }
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"slices"
+	"transcribe/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals("role").(string)
+
+		if slices.Contains(roles, role) {
+			return c.Next()
+		}
+
+		logger.Log.WithField("user_id", c.Locals("user_id")).Warnf("role %q denied", role)
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "insufficient role",
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"slices"
+	"transcribe/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Locals("auth_method") != AuthMethodAPIKey {
+			return c.Next()
+		}
+
+		scopes, _ := c.Locals("scopes").([]string)
+
+		if slices.Contains(scopes, scope) {
+			return c.Next()
+		}
+
+		logger.Log.WithFields(logrus.Fields{
+			"user_id":    c.Locals("user_id"),
+			"api_key_id": c.Locals("api_key_id"),
+		}).Warnf("api key missing scope %s", scope)
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "api key is missing required scope: " + scope,
+		})
+	}
+}
+
+func RequireUserSession(c *fiber.Ctx) error {
+	if c.Locals("auth_method") == AuthMethodAPIKey {
+		logger.Log.WithField("user_id", c.Locals("user_id")).Warn("api key used on session-only endpoint")
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "this endpoint cannot be used with an api key",
+		})
+	}
+
+	return c.Next()
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type LocalStorage struct {
+	root       string
+	baseURL    string
+	signingKey []byte
+}
+
+func NewLocalStorage(root, baseURL, signingKey string) (*LocalStorage, error) {
+	if root == "" {
+		return nil, errors.New("local storage directory is not set")
+	}
+
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+
+	return &LocalStorage{
+		root:       filepath.Clean(root),
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		signingKey: []byte(signingKey),
+	}, nil
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := s.resolve(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.resolve(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return file, err
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.resolve(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	path, err := s.resolve(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ObjectInfo{
+		Key:         key,
+		Size:        info.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(path)),
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
+func (s *LocalStorage) PresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if _, err := s.resolve(key); err != nil {
+		return "", err
+	}
+
+	expires := time.Now().Add(expiry).Unix()
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.sign(key, expires))
+
+	return fmt.Sprintf("%s/api/files/%s?%s", s.baseURL, key, query.Encode()), nil
+}
+
+func (s *LocalStorage) VerifySignature(key string, expires int64, signature string) bool {
+	if time.Now().Unix() > expires {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(s.sign(key, expires)))
+}
+
+func (s *LocalStorage) sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires, 10)))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *LocalStorage) resolve(key string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(key))
+
+	if strings.HasPrefix(cleaned, s.root+string(filepath.Separator)) {
+		return cleaned, nil
+	}
+
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid storage key: %s", key)
+	}
+
+	return filepath.Join(s.root, cleaned), nil
+}
@@ -0,0 +1,277 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is an in-memory stand-in for MinIO that answers the path-style
+// bucket and object requests S3Storage makes.
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]map[string]fakeObject
+}
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+	modTime     time.Time
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, string) {
+	t.Helper()
+
+	fake := &fakeS3{buckets: map[string]map[string]fakeObject{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fake, u.Host
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	objects, ok := f.buckets[bucket]
+
+	if key == "" {
+		switch r.Method {
+		case http.MethodHead:
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case http.MethodPut:
+			if !ok {
+				f.buckets[bucket] = map[string]fakeObject{}
+			}
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+		return
+	}
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := readPayload(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		objects[key] = fakeObject{
+			data:        data,
+			contentType: r.Header.Get("Content-Type"),
+			modTime:     time.Now().UTC(),
+		}
+		w.Header().Set("ETag", `"fake"`)
+	case http.MethodHead, http.MethodGet:
+		object, ok := objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.data)))
+		w.Header().Set("Content-Type", object.contentType)
+		w.Header().Set("Last-Modified", object.modTime.Format(http.TimeFormat))
+		w.Header().Set("ETag", `"fake"`)
+
+		if r.Method == http.MethodGet {
+			w.Write(object.data)
+		}
+	case http.MethodDelete:
+		delete(objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+// readPayload returns the object body, decoding the aws-chunked encoding the
+// client uses for uploads over plain HTTP.
+func readPayload(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+
+	var data bytes.Buffer
+	reader := bufio.NewReader(r.Body)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		if size == 0 {
+			return data.Bytes(), nil
+		}
+
+		if _, err := io.CopyN(&data, reader, size); err != nil {
+			return nil, err
+		}
+
+		if _, err := reader.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func newTestS3Storage(t *testing.T) (*S3Storage, *fakeS3) {
+	t.Helper()
+
+	fake, endpoint := newFakeS3(t)
+
+	s, err := NewS3Storage(Config{
+		S3Endpoint:  endpoint,
+		S3Region:    "us-east-1",
+		S3Bucket:    "transcribe",
+		S3AccessKey: "minioadmin",
+		S3SecretKey: "minioadmin",
+		S3PathStyle: true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+
+	return s, fake
+}
+
+func TestNewS3StorageCreatesBucket(t *testing.T) {
+	_, fake := newTestS3Storage(t)
+
+	if _, ok := fake.buckets["transcribe"]; !ok {
+		t.Fatal("bucket was not created")
+	}
+}
+
+func TestNewS3StorageRequiresEndpointAndBucket(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "missing endpoint", cfg: Config{S3Bucket: "transcribe"}},
+		{name: "missing bucket", cfg: Config{S3Endpoint: "localhost:9000"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewS3Storage(tt.cfg); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	s, _ := newTestS3Storage(t)
+	ctx := context.Background()
+
+	body := []byte("RIFF fake audio")
+	key := "user_1/job.wav"
+
+	if err := s.Put(ctx, key, bytes.NewReader(body), int64(len(body)), "audio/wav"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	info, err := s.Stat(ctx, key)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+
+	if info.Key != key || info.Size != int64(len(body)) || info.ContentType != "audio/wav" {
+		t.Fatalf("Stat = %+v", info)
+	}
+
+	rc, err := s.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatalf("read object: %v", err)
+	}
+
+	if !bytes.Equal(got, body) {
+		t.Fatalf("Open read %q, want %q", got, body)
+	}
+
+	link, err := s.PresignedURL(ctx, key, time.Minute)
+	if err != nil {
+		t.Fatalf("PresignedURL: %v", err)
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("parse presigned url: %v", err)
+	}
+
+	if u.Path != "/transcribe/"+key || u.Query().Get("X-Amz-Signature") == "" || u.Query().Get("X-Amz-Expires") != "60" {
+		t.Fatalf("PresignedURL = %s", link)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, err := s.Stat(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Stat after Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestS3StorageMissingObject(t *testing.T) {
+	s, _ := newTestS3Storage(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "stat", call: func() error {
+			_, err := s.Stat(ctx, "missing.wav")
+			return err
+		}},
+		{name: "open", call: func() error {
+			_, err := s.Open(ctx, "missing.wav")
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("got %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
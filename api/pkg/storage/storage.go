@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	PresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+type Config struct {
+	Driver string
+
+	LocalDir     string
+	LocalBaseURL string
+	SigningKey   string
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+	S3PathStyle bool
+}
+
+func New(cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NewLocalStorage(cfg.LocalDir, cfg.LocalBaseURL, cfg.SigningKey)
+	case DriverS3:
+		return NewS3Storage(cfg)
+	}
+
+	return nil, fmt.Errorf("unsupported storage driver: %s", cfg.Driver)
+}
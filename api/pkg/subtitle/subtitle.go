@@ -0,0 +1,365 @@
+package subtitle
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"transcribe/internal/domain"
+)
+
+type Format string
+
+const (
+	FormatSRT Format = "srt"
+	FormatVTT Format = "vtt"
+)
+
+type Options struct {
+	MaxCharsPerLine int
+	MaxLinesPerCue  int
+	MaxCueDuration  float64
+	IncludeSpeaker  bool
+}
+
+type Cue struct {
+	Index   int
+	Start   float64
+	End     float64
+	Speaker string
+	Lines   []string
+}
+
+func DefaultOptions() Options {
+	return Options{
+		MaxCharsPerLine: 42,
+		MaxLinesPerCue:  2,
+		MaxCueDuration:  7,
+		IncludeSpeaker:  true,
+	}
+}
+
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(value)) {
+	case FormatSRT:
+		return FormatSRT, nil
+	case FormatVTT:
+		return FormatVTT, nil
+	}
+
+	return "", fmt.Errorf("unsupported subtitle format: %s", value)
+}
+
+func (f Format) ContentType() string {
+	if f == FormatVTT {
+		return "text/vtt; charset=utf-8"
+	}
+
+	return "application/x-subrip; charset=utf-8"
+}
+
+func Render(format Format, segments []domain.Segment, opts Options) string {
+	cues := BuildCues(segments, opts)
+
+	if format == FormatVTT {
+		return RenderVTT(cues, opts)
+	}
+
+	return RenderSRT(cues, opts)
+}
+
+func BuildCues(segments []domain.Segment, opts Options) []Cue {
+	opts = normalize(opts)
+
+	var cues []Cue
+
+	for _, seg := range segments {
+		words, timed := segmentWords(seg)
+
+		if len(words) == 0 || seg.EndTime <= seg.StartTime {
+			continue
+		}
+
+		if timed {
+			cues = append(cues, splitTimed(seg, words, opts)...)
+			continue
+		}
+
+		for _, group := range splitSegment(words, seg.EndTime-seg.StartTime, opts) {
+			cues = append(cues, Cue{
+				Start:   seg.StartTime + group.startOffset*(seg.EndTime-seg.StartTime),
+				End:     seg.StartTime + group.endOffset*(seg.EndTime-seg.StartTime),
+				Speaker: seg.Speaker,
+				Lines:   group.lines,
+			})
+		}
+	}
+
+	for i := range cues {
+		cues[i].Index = i + 1
+	}
+
+	return cues
+}
+
+func RenderSRT(cues []Cue, opts Options) string {
+	var b strings.Builder
+
+	for _, cue := range cues {
+		fmt.Fprintf(&b, "%d\n", cue.Index)
+		fmt.Fprintf(&b, "%s --> %s\n", formatTimestamp(cue.Start, ","), formatTimestamp(cue.End, ","))
+
+		lines := cue.Lines
+		if opts.IncludeSpeaker && cue.Speaker != "" {
+			lines = withSpeakerPrefix(lines, cue.Speaker+": ")
+		}
+
+		b.WriteString(strings.Join(lines, "\n"))
+		b.WriteString("\n\n")
+	}
+
+	return b.String()
+}
+
+func RenderVTT(cues []Cue, opts Options) string {
+	var b strings.Builder
+
+	b.WriteString("WEBVTT\n\n")
+
+	for _, cue := range cues {
+		fmt.Fprintf(&b, "%d\n", cue.Index)
+		fmt.Fprintf(&b, "%s --> %s\n", formatTimestamp(cue.Start, "."), formatTimestamp(cue.End, "."))
+
+		lines := make([]string, len(cue.Lines))
+		for i, line := range cue.Lines {
+			lines[i] = escapeVTT(line)
+		}
+
+		if opts.IncludeSpeaker && cue.Speaker != "" {
+			lines = withSpeakerPrefix(lines, "<v "+escapeVTT(cue.Speaker)+">")
+		}
+
+		b.WriteString(strings.Join(lines, "\n"))
+		b.WriteString("\n\n")
+	}
+
+	return b.String()
+}
+
+type cueGroup struct {
+	lines       []string
+	startOffset float64
+	endOffset   float64
+}
+
+// splitTimed cuts a segment with word timestamps into cues. Each cue takes
+// words while they fit in MaxLinesPerCue lines and end within MaxCueDuration
+// of its first word, so pauses between words count towards the limit. A
+// single word that is longer than MaxCueDuration is cut short.
+func splitTimed(seg domain.Segment, words []string, opts Options) []Cue {
+	var cues []Cue
+
+	for first := 0; first < len(words); {
+		start := math.Max(seg.Words[first].Start, seg.StartTime)
+		next := first + 1
+
+		for next < len(words) {
+			if len(wrapWords(words[first:next+1], opts.MaxCharsPerLine)) > opts.MaxLinesPerCue {
+				break
+			}
+
+			if opts.MaxCueDuration > 0 && math.Min(seg.Words[next].End, seg.EndTime)-start > opts.MaxCueDuration {
+				break
+			}
+
+			next++
+		}
+
+		end := math.Max(math.Min(seg.Words[next-1].End, seg.EndTime), start)
+
+		if opts.MaxCueDuration > 0 && end-start > opts.MaxCueDuration {
+			end = start + opts.MaxCueDuration
+		}
+
+		cues = append(cues, Cue{
+			Start:   start,
+			End:     end,
+			Speaker: seg.Speaker,
+			Lines:   wrapWords(words[first:next], opts.MaxCharsPerLine),
+		})
+
+		first = next
+	}
+
+	return cues
+}
+
+// splitSegment cuts words without timestamps into cues of balanced length.
+// Cue times are later spread over the segment by character count.
+func splitSegment(words []string, duration float64, opts Options) []cueGroup {
+	minCues := 1
+	if opts.MaxCueDuration > 0 {
+		minCues = int(math.Ceil(duration / opts.MaxCueDuration))
+	}
+
+	if wrapped := len(wrapWords(words, opts.MaxCharsPerLine)); wrapped > opts.MaxLinesPerCue*minCues {
+		minCues = int(math.Ceil(float64(wrapped) / float64(opts.MaxLinesPerCue)))
+	}
+
+	for n := min(minCues, len(words)); ; n++ {
+		chunks := balanceWords(words, n)
+		groups := make([]cueGroup, 0, len(chunks))
+		fits := true
+
+		for _, chunk := range chunks {
+			lines := wrapWords(chunk, opts.MaxCharsPerLine)
+			if len(lines) > opts.MaxLinesPerCue {
+				fits = false
+				break
+			}
+			groups = append(groups, cueGroup{lines: lines})
+		}
+
+		if fits || n >= len(words) {
+			assignOffsets(groups, chunks)
+			return groups
+		}
+	}
+}
+
+func segmentWords(seg domain.Segment) ([]string, bool) {
+	if len(seg.Words) > 0 {
+		words := make([]string, 0, len(seg.Words))
+
+		for _, w := range seg.Words {
+			text := strings.TrimSpace(w.Text)
+			if text == "" || w.End < w.Start {
+				return strings.Fields(seg.Text), false
+			}
+			words = append(words, text)
+		}
+
+		return words, true
+	}
+
+	return strings.Fields(seg.Text), false
+}
+
+func balanceWords(words []string, n int) [][]string {
+	if n <= 1 {
+		return [][]string{words}
+	}
+
+	total := 0
+	for _, w := range words {
+		total += len([]rune(w)) + 1
+	}
+
+	target := float64(total) / float64(n)
+	chunks := make([][]string, 0, n)
+	start, acc := 0, 0
+
+	for i, w := range words {
+		acc += len([]rune(w)) + 1
+		remainingWords := len(words) - i - 1
+		remainingChunks := n - len(chunks) - 1
+
+		if remainingChunks > 0 && (float64(acc) >= target*float64(len(chunks)+1) || remainingWords == remainingChunks) {
+			chunks = append(chunks, words[start:i+1])
+			start = i + 1
+		}
+	}
+
+	return append(chunks, words[start:])
+}
+
+func wrapWords(words []string, maxChars int) []string {
+	var lines []string
+	var current string
+
+	for _, w := range words {
+		if current == "" {
+			current = w
+			continue
+		}
+
+		if len([]rune(current))+1+len([]rune(w)) > maxChars {
+			lines = append(lines, current)
+			current = w
+			continue
+		}
+
+		current += " " + w
+	}
+
+	if current != "" {
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+func assignOffsets(groups []cueGroup, chunks [][]string) {
+	weights := make([]int, len(chunks))
+	total := 0
+
+	for i, chunk := range chunks {
+		for _, w := range chunk {
+			weights[i] += len([]rune(w)) + 1
+		}
+		total += weights[i]
+	}
+
+	acc := 0
+	for i := range groups {
+		groups[i].startOffset = float64(acc) / float64(total)
+		acc += weights[i]
+		groups[i].endOffset = float64(acc) / float64(total)
+	}
+}
+
+func normalize(opts Options) Options {
+	defaults := DefaultOptions()
+
+	if opts.MaxCharsPerLine <= 0 {
+		opts.MaxCharsPerLine = defaults.MaxCharsPerLine
+	}
+
+	if opts.MaxLinesPerCue <= 0 {
+		opts.MaxLinesPerCue = defaults.MaxLinesPerCue
+	}
+
+	if opts.MaxCueDuration < 0 {
+		opts.MaxCueDuration = 0
+	}
+
+	return opts
+}
+
+func withSpeakerPrefix(lines []string, prefix string) []string {
+	if len(lines) == 0 {
+		return lines
+	}
+
+	prefixed := append([]string{}, lines...)
+	prefixed[0] = prefix + prefixed[0]
+
+	return prefixed
+}
+
+func escapeVTT(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+func formatTimestamp(seconds float64, msSeparator string) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	totalMs := int64(math.Round(seconds * 1000))
+	hours := totalMs / 3600000
+	minutes := (totalMs % 3600000) / 60000
+	secs := (totalMs % 60000) / 1000
+	ms := totalMs % 1000
+
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, secs, msSeparator, ms)
+}
@@ -0,0 +1,239 @@
+package subtitle
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"transcribe/internal/domain"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	tests := []struct {
+		seconds   float64
+		separator string
+		want      string
+	}{
+		{seconds: 0, separator: ",", want: "00:00:00,000"},
+		{seconds: 1.9995, separator: ",", want: "00:00:02,000"},
+		{seconds: 1.9994, separator: ",", want: "00:00:01,999"},
+		{seconds: 59.9999, separator: ".", want: "00:01:00.000"},
+		{seconds: 3723.456, separator: ".", want: "01:02:03.456"},
+		{seconds: -1, separator: ",", want: "00:00:00,000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatTimestamp(tt.seconds, tt.separator); got != tt.want {
+				t.Fatalf("formatTimestamp(%v, %q) = %q, want %q", tt.seconds, tt.separator, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []string
+		maxChars int
+		want     []string
+	}{
+		{name: "fits exactly", words: []string{"abcd", "efghi"}, maxChars: 10, want: []string{"abcd efghi"}},
+		{name: "one over", words: []string{"abcd", "efghij"}, maxChars: 10, want: []string{"abcd", "efghij"}},
+		{name: "long word keeps its own line", words: []string{"ab", "abcdefghijkl", "cd"}, maxChars: 10, want: []string{"ab", "abcdefghijkl", "cd"}},
+		{name: "runes not bytes", words: []string{"überall", "ça"}, maxChars: 10, want: []string{"überall ça"}},
+		{name: "empty", words: nil, maxChars: 10, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapWords(tt.words, tt.maxChars); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("wrapWords = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeVTT(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "plain", want: "plain"},
+		{text: "a < b > c", want: "a &lt; b &gt; c"},
+		{text: "R&D", want: "R&amp;D"},
+		{text: "&lt;", want: "&amp;lt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := escapeVTT(tt.text); got != tt.want {
+				t.Fatalf("escapeVTT(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func timedWords(text string, start, step float64) []domain.Word {
+	var words []domain.Word
+
+	for i, w := range strings.Fields(text) {
+		at := start + float64(i)*step
+		words = append(words, domain.Word{Text: " " + w, Start: at, End: at + step*0.8})
+	}
+
+	return words
+}
+
+type cueTimes struct {
+	start, end float64
+	lines      []string
+}
+
+func TestBuildCues(t *testing.T) {
+	paused := timedWords("before the pause", 0, 0.5)
+	paused = append(paused, timedWords("after it", 9, 0.5)...)
+
+	tests := []struct {
+		name    string
+		segment domain.Segment
+		opts    Options
+		want    []cueTimes
+	}{
+		{
+			name:    "short segment is one cue",
+			segment: domain.Segment{StartTime: 1, EndTime: 3, Text: "Halo semua."},
+			opts:    DefaultOptions(),
+			want:    []cueTimes{{1, 3, []string{"Halo semua."}}},
+		},
+		{
+			name:    "untimed words split by lines",
+			segment: domain.Segment{StartTime: 0, EndTime: 4, Text: "one two three four five six"},
+			opts:    Options{MaxCharsPerLine: 9, MaxLinesPerCue: 1},
+			want: []cueTimes{
+				{0, 4 * 8.0 / 28, []string{"one two"}},
+				{4 * 8.0 / 28, 4 * 14.0 / 28, []string{"three"}},
+				{4 * 14.0 / 28, 4 * 24.0 / 28, []string{"four five"}},
+				{4 * 24.0 / 28, 4, []string{"six"}},
+			},
+		},
+		{
+			name:    "untimed words split by duration",
+			segment: domain.Segment{StartTime: 0, EndTime: 10, Text: "aaaa bbbb cccc dddd"},
+			opts:    Options{MaxCueDuration: 5},
+			want: []cueTimes{
+				{0, 5, []string{"aaaa bbbb"}},
+				{5, 10, []string{"cccc dddd"}},
+			},
+		},
+		{
+			name:    "timed words split by lines",
+			segment: domain.Segment{StartTime: 0, EndTime: 3, Text: "one two three four five six", Words: timedWords("one two three four five six", 0, 0.5)},
+			opts:    Options{MaxCharsPerLine: 9, MaxLinesPerCue: 1},
+			want: []cueTimes{
+				{0, 0.9, []string{"one two"}},
+				{1, 1.4, []string{"three"}},
+				{1.5, 2.4, []string{"four five"}},
+				{2.5, 2.9, []string{"six"}},
+			},
+		},
+		{
+			name:    "timed words split at a long pause",
+			segment: domain.Segment{StartTime: 0, EndTime: 10, Text: "before the pause after it", Words: paused},
+			opts:    Options{MaxCueDuration: 7},
+			want: []cueTimes{
+				{0, 1.4, []string{"before the pause"}},
+				{9, 9.9, []string{"after it"}},
+			},
+		},
+		{
+			name: "timed word longer than the limit is cut short",
+			segment: domain.Segment{StartTime: 0, EndTime: 12, Text: "hmmm", Words: []domain.Word{
+				{Text: " hmmm", Start: 1, End: 11},
+			}},
+			opts: Options{MaxCueDuration: 7},
+			want: []cueTimes{{1, 8, []string{"hmmm"}}},
+		},
+		{
+			name: "invalid word timestamps fall back to text",
+			segment: domain.Segment{StartTime: 0, EndTime: 2, Text: "Halo semua.", Words: []domain.Word{
+				{Text: " Halo", Start: 1, End: 0.5},
+			}},
+			opts: DefaultOptions(),
+			want: []cueTimes{{0, 2, []string{"Halo semua."}}},
+		},
+		{
+			name:    "empty segment is skipped",
+			segment: domain.Segment{StartTime: 0, EndTime: 2, Text: "  "},
+			opts:    DefaultOptions(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cues := BuildCues([]domain.Segment{tt.segment}, tt.opts)
+
+			if len(cues) != len(tt.want) {
+				t.Fatalf("BuildCues returned %d cues, want %d: %+v", len(cues), len(tt.want), cues)
+			}
+
+			for i, cue := range cues {
+				want := tt.want[i]
+
+				if cue.Index != i+1 || math.Abs(cue.Start-want.start) > 1e-9 || math.Abs(cue.End-want.end) > 1e-9 ||
+					!reflect.DeepEqual(cue.Lines, want.lines) {
+					t.Fatalf("cue %d = %+v, want %+v", i, cue, want)
+				}
+
+				if tt.opts.MaxCueDuration > 0 && cue.End-cue.Start > tt.opts.MaxCueDuration+1e-9 {
+					t.Fatalf("cue %d lasts %.3fs, longer than %.3fs", i, cue.End-cue.Start, tt.opts.MaxCueDuration)
+				}
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	segments := []domain.Segment{
+		{StartTime: 0, EndTime: 1.9995, Text: "a < b", Speaker: "SPEAKER_00"},
+		{StartTime: 2, EndTime: 3.5, Text: "done"},
+	}
+
+	tests := []struct {
+		name   string
+		format Format
+		opts   Options
+		want   string
+	}{
+		{
+			name:   "srt",
+			format: FormatSRT,
+			opts:   DefaultOptions(),
+			want: "1\n00:00:00,000 --> 00:00:02,000\nSPEAKER_00: a < b\n\n" +
+				"2\n00:00:02,000 --> 00:00:03,500\ndone\n\n",
+		},
+		{
+			name:   "vtt",
+			format: FormatVTT,
+			opts:   DefaultOptions(),
+			want: "WEBVTT\n\n" +
+				"1\n00:00:00.000 --> 00:00:02.000\n<v SPEAKER_00>a &lt; b\n\n" +
+				"2\n00:00:02.000 --> 00:00:03.500\ndone\n\n",
+		},
+		{
+			name:   "without speakers",
+			format: FormatSRT,
+			opts:   Options{},
+			want: "1\n00:00:00,000 --> 00:00:02,000\na < b\n\n" +
+				"2\n00:00:02,000 --> 00:00:03,500\ndone\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.format, segments, tt.opts); got != tt.want {
+				t.Fatalf("Render =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
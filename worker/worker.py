@@ -1,7 +1,11 @@
 import os
 import json
 import time
+import socket
+import shutil
+import tempfile
 import logging
+import threading
 from datetime import datetime
 import redis
 import mysql.connector
@@ -23,6 +27,75 @@ REDIS_HOST = os.getenv('REDIS_HOST', 'localhost')
 REDIS_PORT = int(os.getenv('REDIS_PORT', 6379))
 REDIS_PASSWORD = os.getenv('REDIS_PASSWORD', '')
 REDIS_QUEUE = 'transcription_queue'
+REDIS_LEASES = f'{REDIS_QUEUE}:leases'
+REDIS_WORKERS = f'{REDIS_QUEUE}:workers'
+WORKER_ID = os.getenv('WORKER_ID', f'{socket.gethostname()}-{os.getpid()}')
+VISIBILITY_TIMEOUT = int(os.getenv('QUEUE_VISIBILITY_TIMEOUT_SECONDS', 600))
+HEARTBEAT_INTERVAL = max(1, VISIBILITY_TIMEOUT // 3)
+REDIS_DELAYED = f'{REDIS_QUEUE}:delayed'
+REDIS_DEAD = f'{REDIS_QUEUE}:dead'
+PROGRESS_STREAM_MAXLEN = 500
+PROGRESS_STREAM_TTL = 7 * 24 * 3600
+MAX_ATTEMPTS = int(os.getenv('QUEUE_MAX_ATTEMPTS', 3))
+RETRY_BASE_SECONDS = int(os.getenv('QUEUE_RETRY_BASE_SECONDS', 30))
+RETRY_MAX_SECONDS = int(os.getenv('QUEUE_RETRY_MAX_SECONDS', 1800))
+
+# Statuses a job may be in when the worker moves it to the key status. Mirrors
+# the state machine in the API (domain/job_status.go); an update from any other
+# status is skipped so a cancelled job is never brought back.
+STATUS_ALLOWED_FROM = {
+    'processing': ('queued', 'retrying'),
+    'retrying': ('queued', 'processing'),
+    'done': ('processing',),
+    'failed': ('queued', 'processing', 'retrying'),
+    'cancelled': ('cancelling',),
+}
+
+
+# Queue moves are single Lua scripts, the same as in the API
+# (internal/queue/redis_queue.go). Each one first removes the payload from this
+# worker's processing list and does nothing if it is no longer there, so a
+# worker whose job was already reaped and handed to another worker cannot touch
+# that worker's lease.
+REQUEUE_SCRIPT = """
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+    return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[2])
+redis.call('ZADD', KEYS[3], ARGV[4], ARGV[3])
+return 1
+"""
+
+DEAD_LETTER_SCRIPT = """
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+    return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[2])
+redis.call('LPUSH', KEYS[3], ARGV[3])
+return 1
+"""
+
+ACK_SCRIPT = """
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+    return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[2])
+return 1
+"""
+
+HEARTBEAT_SCRIPT = """
+if not redis.call('LPOS', KEYS[1], ARGV[1]) then
+    return 0
+end
+redis.call('ZADD', KEYS[2], ARGV[3], ARGV[2])
+redis.call('SET', KEYS[3], ARGV[4], 'EX', ARGV[5])
+return 1
+"""
+
+
+def allowed_from(status):
+    statuses = STATUS_ALLOWED_FROM[status]
+    return ', '.join(['%s'] * len(statuses)), statuses
 
 DB_CONFIG = {
     'host': os.getenv('DB_HOST', 'localhost'),
@@ -32,6 +105,16 @@ DB_CONFIG = {
     'database': os.getenv('DB_NAME', 'transcribe')
 }
 
+STORAGE_DRIVER = os.getenv('STORAGE_DRIVER', 'local')
+UPLOAD_DIR = os.getenv('UPLOAD_DIR', '')
+S3_ENDPOINT = os.getenv('S3_ENDPOINT', '')
+S3_REGION = os.getenv('S3_REGION', 'us-east-1')
+S3_BUCKET = os.getenv('S3_BUCKET', '')
+S3_ACCESS_KEY = os.getenv('S3_ACCESS_KEY', '')
+S3_SECRET_KEY = os.getenv('S3_SECRET_KEY', '')
+S3_USE_SSL = os.getenv('S3_USE_SSL', 'true').lower() == 'true'
+S3_PATH_STYLE = os.getenv('S3_PATH_STYLE', 'false').lower() == 'true'
+
 MODEL_SIZE = os.getenv('WHISPER_MODEL', 'small')
 DIARIZATION_METHOD = os.getenv('DIARIZATION_METHOD', 'none')
 HF_TOKEN = os.getenv('HUGGINGFACE_TOKEN', '')
@@ -39,24 +122,65 @@ HF_TOKEN = os.getenv('HUGGINGFACE_TOKEN', '')
 class JobCancelledException(Exception):
     pass
 
-def run_whisper_inference(model, file_path, queue):
+NON_RETRYABLE_ERRORS = (FileNotFoundError, ValueError)
+
+def run_whisper_inference(model, file_path, queue, language=None, task='transcribe'):
     try:
         result = model.transcribe(
             file_path,
             word_timestamps=True,
-            verbose=False
+            verbose=False,
+            language=language,
+            task=task
         )
         queue.put({"success": True, "result": result})
     except Exception as e:
         queue.put({"success": False, "error": str(e)})
 
+class LeaseHeartbeat:
+    """Renews the lease of a claimed job on a background thread for as long as
+    the job is held, so slow downloads, model loads or diarization do not let
+    the lease expire while the job is still running."""
+
+    def __init__(self, worker, raw, job_id):
+        self.worker = worker
+        self.raw = raw
+        self.job_id = job_id
+        self.stopped = threading.Event()
+        self.thread = threading.Thread(target=self.run, name=f"heartbeat-{job_id}", daemon=True)
+
+    def start(self):
+        self.thread.start()
+        return self
+
+    def run(self):
+        while not self.stopped.wait(HEARTBEAT_INTERVAL):
+            try:
+                if not self.worker.heartbeat(self.raw, self.job_id):
+                    logger.warning(f"lease of job {self.job_id} was lost, no longer renewing it")
+                    return
+            except Exception as e:
+                logger.error(f"failed to renew lease of job {self.job_id}: {e}")
+
+    def stop(self):
+        self.stopped.set()
+        self.thread.join()
+
 class TranscriptionWorker:
     def __init__(self):
         self.redis_client = None
         self.db_connection = None
         self.model = None
+        self.models = {}
         self.voice_encoder = None
         self.pyannote_pipeline = None
+        self.s3_client = None
+        self.processing_key = f"{REDIS_QUEUE}:processing:{WORKER_ID}"
+        self.worker_key = f"{REDIS_QUEUE}:worker:{WORKER_ID}"
+        self.requeue_script = None
+        self.dead_letter_script = None
+        self.ack_script = None
+        self.heartbeat_script = None
     
     def connect(self):
         try:
@@ -70,84 +194,198 @@ class TranscriptionWorker:
             self.redis_client.ping()
             logger.info("connected to redis")
 
+            self.requeue_script = self.redis_client.register_script(REQUEUE_SCRIPT)
+            self.dead_letter_script = self.redis_client.register_script(DEAD_LETTER_SCRIPT)
+            self.ack_script = self.redis_client.register_script(ACK_SCRIPT)
+            self.heartbeat_script = self.redis_client.register_script(HEARTBEAT_SCRIPT)
+
             self.db_connection = mysql.connector.connect(**DB_CONFIG)
             logger.info("connected to mysql")
 
-            logger.info(f"loading whisper model: {MODEL_SIZE}")
-            self.model = whisper.load_model(MODEL_SIZE)
-            logger.info(f"whisper model loaded: {MODEL_SIZE}")
-
-            if DIARIZATION_METHOD == 'resemblyzer':
-                logger.info("loading resemblyzer voice encoder...")
-                self.voice_encoder = VoiceEncoder()
-                logger.info("resemblyzer loaded (no huggingface needed)")
-            elif DIARIZATION_METHOD == 'pyannote':
-                if not HF_TOKEN:
-                    logger.warning("pyannote selected but no huggingface token provided")
-                else:
-                    from pyannote.audio import Pipeline
-                    logger.info("loading pyannote Speaker diarization...")
-                    self.pyannote_pipeline = Pipeline.from_pretrained(
-                        "pyannote/speaker-diarization-3.1",
-                        use_auth_token=HF_TOKEN
-                    )
-                    if torch.cuda.is_available():
-                        self.pyannote_pipeline.to(torch.device("cuda"))
-                        logger.info("pyannote loaded (GPU)")
-                    else:
-                        logger.info("pyannote loaded (CPU)")
-            elif DIARIZATION_METHOD == 'simple':
-                logger.info("simple speaker detection enabled (no extra dependencies)")
+            if STORAGE_DRIVER == 's3':
+                import boto3
+                from botocore.config import Config
+                scheme = 'https' if S3_USE_SSL else 'http'
+                self.s3_client = boto3.client(
+                    's3',
+                    endpoint_url=f"{scheme}://{S3_ENDPOINT}",
+                    region_name=S3_REGION,
+                    aws_access_key_id=S3_ACCESS_KEY,
+                    aws_secret_access_key=S3_SECRET_KEY,
+                    config=Config(s3={'addressing_style': 'path' if S3_PATH_STYLE else 'auto'})
+                )
+                logger.info(f"using s3 storage: {S3_ENDPOINT}/{S3_BUCKET}")
             else:
-                logger.info("speaker diarization disabled")
+                logger.info(f"using local storage: {UPLOAD_DIR}")
+
+            self.model = self.get_model(MODEL_SIZE)
+            self.load_diarization(DIARIZATION_METHOD)
 
         except Exception as e:
             logger.error(f"connection error: {e}")
             raise
+
+    def fetch_input(self, job_data):
+        key = job_data.get('storage_key') or job_data.get('file_path')
+
+        if STORAGE_DRIVER == 's3':
+            suffix = os.path.splitext(key)[1]
+            tmp_dir = tempfile.mkdtemp(prefix='transcribe-')
+            local_path = os.path.join(tmp_dir, f"input{suffix}")
+            try:
+                self.s3_client.download_file(S3_BUCKET, key, local_path)
+            except Exception as e:
+                shutil.rmtree(tmp_dir, ignore_errors=True)
+                if getattr(e, 'response', {}).get('Error', {}).get('Code') in ('404', 'NoSuchKey'):
+                    raise FileNotFoundError(f"file not found: {key}")
+                raise
+            return local_path, tmp_dir
+
+        local_path = key
+        if not os.path.isabs(key) and not key.startswith(os.path.normpath(UPLOAD_DIR) + os.sep):
+            local_path = os.path.join(UPLOAD_DIR, key)
+
+        if not os.path.exists(local_path):
+            raise FileNotFoundError(f"file not found: {local_path}")
+
+        return local_path, None
+
+    def get_model(self, model_size):
+        if model_size not in self.models:
+            logger.info(f"loading whisper model: {model_size}")
+            self.models[model_size] = whisper.load_model(model_size)
+            logger.info(f"whisper model loaded: {model_size}")
+        return self.models[model_size]
+
+    def load_diarization(self, method):
+        if method == 'resemblyzer':
+            if self.voice_encoder is None:
+                logger.info("loading resemblyzer voice encoder...")
+                self.voice_encoder = VoiceEncoder()
+                logger.info("resemblyzer loaded (no huggingface needed)")
+        elif method == 'pyannote':
+            if self.pyannote_pipeline is not None:
+                return
+            if not HF_TOKEN:
+                logger.warning("pyannote selected but no huggingface token provided")
+            else:
+                from pyannote.audio import Pipeline
+                logger.info("loading pyannote Speaker diarization...")
+                self.pyannote_pipeline = Pipeline.from_pretrained(
+                    "pyannote/speaker-diarization-3.1",
+                    use_auth_token=HF_TOKEN
+                )
+                if torch.cuda.is_available():
+                    self.pyannote_pipeline.to(torch.device("cuda"))
+                    logger.info("pyannote loaded (GPU)")
+                else:
+                    logger.info("pyannote loaded (CPU)")
+        elif method == 'simple':
+            logger.info("simple speaker detection enabled (no extra dependencies)")
+        else:
+            logger.info("speaker diarization disabled")
     
-    def update_job_status(self, job_id, status, text=None, error_msg=None, segments=None, duration=None):
+    def update_job_status(self, job_id, status, text=None, error_msg=None, segments=None, duration=None, attempts=None):
         try:
             cursor = self.db_connection.cursor()
+            placeholders, from_statuses = allowed_from(status)
 
             if status == 'processing':
-                query = """
+                query = f"""
                     UPDATE transcription_jobs
                     SET status = %s, updated_at = %s
-                    WHERE id = %s
+                    WHERE id = %s AND status IN ({placeholders})
                 """
-                cursor.execute(query, (status, datetime.now(), job_id))
+                cursor.execute(query, (status, datetime.now(), job_id, *from_statuses))
             elif status == 'done':
                 segments_json = json.dumps(segments) if segments else None
                 
-                query = """
+                # the api stores the duration probed from the file; only fill it
+                # in when the probe could not tell, e.g. a flac without a length
+                query = f"""
                     UPDATE transcription_jobs 
-                    SET status = %s, text = %s, segments = %s, duration = %s,
+                    SET status = %s, text = %s, segments = %s,
+                        duration = COALESCE(NULLIF(duration, 0), %s),
                         completed_at = %s, updated_at = %s 
-                    WHERE id = %s
+                    WHERE id = %s AND status IN ({placeholders})
                 """
                 cursor.execute(query, (
                     status, text, segments_json, duration,
-                    datetime.now(), datetime.now(), job_id
+                    datetime.now(), datetime.now(), job_id, *from_statuses
                 ))
             elif status == 'failed':
-                query = """
+                query = f"""
                     UPDATE transcription_jobs 
-                    SET status = %s, error_msg = %s, completed_at = %s, updated_at = %s 
-                    WHERE id = %s
+                    SET status = %s, error_msg = %s, attempts = COALESCE(%s, attempts),
+                        next_retry_at = NULL, completed_at = %s, updated_at = %s 
+                    WHERE id = %s AND status IN ({placeholders})
                 """
-                cursor.execute(query, (status, error_msg, datetime.now(), datetime.now(), job_id))
+                cursor.execute(query, (
+                    status, error_msg, attempts, datetime.now(), datetime.now(),
+                    job_id, *from_statuses
+                ))
+            elif status == 'cancelled':
+                query = f"""
+                    UPDATE transcription_jobs
+                    SET status = %s, updated_at = %s
+                    WHERE id = %s AND status IN ({placeholders})
+                """
+                cursor.execute(query, (status, datetime.now(), job_id, *from_statuses))
             
+            updated = cursor.rowcount
             self.db_connection.commit()
             cursor.close()
+
+            if not updated:
+                logger.warning(f"job {job_id} not moved to {status}: status changed meanwhile")
+                return False
+
             logger.info(f"job {job_id} status updated to: {status}")
 
-            self.publish_progress(job_id, status)
+            message = error_msg
+            if status == 'processing':
+                message = f"claimed by worker {WORKER_ID}"
+            elif status == 'cancelled':
+                message = f"cancellation acknowledged by worker {WORKER_ID}"
+            self.publish_progress(job_id, status, message=message)
+            return True
 
         except Exception as e:
             logger.error(f"database error updating job {job_id}: {e}")
             self.db_connection.rollback()
+            return False
     
-    def publish_progress(self, job_id, status, progress=None):
+    def get_job_status(self, job_id):
+        try:
+            cursor = self.db_connection.cursor()
+            cursor.execute("SELECT status FROM transcription_jobs WHERE id = %s AND deleted_at IS NULL", (job_id,))
+            row = cursor.fetchone()
+            cursor.close()
+            self.db_connection.commit()
+            return row[0] if row else None
+        except Exception as e:
+            logger.error(f"database error reading job {job_id}: {e}")
+            return None
+
+    def record_event(self, job_id, stage, progress=None, message=None):
+        try:
+            cursor = self.db_connection.cursor()
+            cursor.execute(
+                """
+                INSERT INTO job_events (job_id, stage, progress, message, actor, created_at)
+                VALUES (%s, %s, %s, %s, %s, %s)
+                """,
+                (job_id, stage, progress, message, 'worker', datetime.now())
+            )
+            self.db_connection.commit()
+            cursor.close()
+        except Exception as e:
+            logger.error(f"database error recording event for job {job_id}: {e}")
+            self.db_connection.rollback()
+
+    def publish_progress(self, job_id, status, progress=None, message=None):
+        self.record_event(job_id, status, progress, message)
+
         try:
             message = {
                 "job_id": job_id,
@@ -156,7 +394,14 @@ class TranscriptionWorker:
             }
             if progress is not None:
                 message["progress"] = progress
-            
+
+            stream = f"job_events:{job_id}"
+            message["event_id"] = self.redis_client.xadd(
+                stream, {"data": json.dumps(message)},
+                maxlen=PROGRESS_STREAM_MAXLEN, approximate=True
+            )
+            self.redis_client.expire(stream, PROGRESS_STREAM_TTL)
+
             self.redis_client.publish(f"job_progress:{job_id}", json.dumps(message))
             logger.debug(f"published progress for {job_id}: {status}")
         except Exception as e:
@@ -167,14 +412,122 @@ class TranscriptionWorker:
         secs = int(seconds % 60)
         return f"{minutes:02d}:{secs:02d}"
 
+    def claim_job(self):
+        pipe = self.redis_client.pipeline()
+        pipe.sadd(REDIS_WORKERS, WORKER_ID)
+        pipe.set(self.worker_key, int(time.time()), ex=VISIBILITY_TIMEOUT)
+        pipe.execute()
+
+        raw = self.redis_client.blmove(REDIS_QUEUE, self.processing_key, 5, 'LEFT', 'RIGHT')
+        if not raw:
+            return None, None
+
+        try:
+            job_data = json.loads(raw)
+        except ValueError:
+            logger.error(f"dropping malformed queue payload: {raw}")
+            self.redis_client.lrem(self.processing_key, 1, raw)
+            return None, None
+
+        self.heartbeat(raw, job_data['job_id'])
+        return raw, job_data
+
+    def heartbeat(self, raw, job_id):
+        now = int(time.time())
+        renewed = self.heartbeat_script(
+            keys=[self.processing_key, REDIS_LEASES, self.worker_key],
+            args=[raw, job_id, now + VISIBILITY_TIMEOUT, now, VISIBILITY_TIMEOUT]
+        )
+        return renewed == 1
+
+    def ack_job(self, raw, job_id):
+        # A job that was requeued or dead-lettered is already gone from the
+        # processing list, and then the lease is not ours to remove.
+        self.ack_script(keys=[self.processing_key, REDIS_LEASES], args=[raw, job_id])
+
+    def retry_delay(self, attempt):
+        return min(RETRY_BASE_SECONDS * (2 ** (max(attempt, 1) - 1)), RETRY_MAX_SECONDS)
+
+    def handle_failure(self, raw, job_data, error_msg, retryable=True):
+        job_id = job_data['job_id']
+        attempts = int(job_data.get('attempts', 0)) + 1
+        max_attempts = int(job_data.get('max_attempts') or MAX_ATTEMPTS)
+
+        if retryable and attempts < max_attempts:
+            delay = self.retry_delay(attempts)
+            next_run = time.time() + delay
+            payload = dict(job_data, attempts=attempts)
+
+            moved = self.requeue_script(
+                keys=[self.processing_key, REDIS_LEASES, REDIS_DELAYED],
+                args=[raw, job_id, json.dumps(payload), int(next_run)]
+            )
+            if not moved:
+                logger.warning(f"job {job_id} is no longer held by this worker, not scheduling a retry")
+                return
+
+            self.update_job_retry(job_id, attempts, datetime.fromtimestamp(next_run), error_msg)
+            logger.warning(f"job {job_id} attempt {attempts}/{max_attempts} failed, retrying in {delay}s")
+            return
+
+        dead = {
+            'job': dict(job_data, attempts=attempts),
+            'error': error_msg,
+            'failed_at': datetime.now().astimezone().isoformat()
+        }
+        moved = self.dead_letter_script(
+            keys=[self.processing_key, REDIS_LEASES, REDIS_DEAD],
+            args=[raw, job_id, json.dumps(dead)]
+        )
+        if not moved:
+            logger.warning(f"job {job_id} is no longer held by this worker, not dead-lettering it")
+            return
+
+        self.update_job_status(job_id, 'failed', error_msg=error_msg, attempts=attempts)
+        logger.error(f"job {job_id} moved to dead-letter queue after {attempts} attempts")
+
+    def update_job_retry(self, job_id, attempts, next_retry_at, error_msg):
+        try:
+            cursor = self.db_connection.cursor()
+            placeholders, from_statuses = allowed_from('retrying')
+            query = f"""
+                UPDATE transcription_jobs
+                SET status = %s, attempts = %s, next_retry_at = %s, error_msg = %s, updated_at = %s
+                WHERE id = %s AND status IN ({placeholders})
+            """
+            cursor.execute(query, (
+                'retrying', attempts, next_retry_at, error_msg, datetime.now(),
+                job_id, *from_statuses
+            ))
+            updated = cursor.rowcount
+            self.db_connection.commit()
+            cursor.close()
+
+            if not updated:
+                logger.warning(f"job {job_id} not moved to retrying: status changed meanwhile")
+                return
+
+            self.publish_progress(job_id, 'retrying', message=error_msg)
+
+        except Exception as e:
+            logger.error(f"database error scheduling retry for job {job_id}: {e}")
+            self.db_connection.rollback()
+
     def check_cancellation(self, job_id):
         key = f"job_cancellation:{job_id}"
         if self.redis_client.exists(key):
             logger.warning(f"job {job_id} was cancelled by user")
             self.redis_client.delete(key)
             raise JobCancelledException("Job was cancelled by user")
+
+    def acknowledge_cancellation(self, job_id):
+        # Only moves a job in cancelling; the reaper in the API may have
+        # cancelled it already.
+        self.redis_client.delete(f"job_cancellation:{job_id}")
+        if self.update_job_status(job_id, 'cancelled'):
+            logger.warning(f"job {job_id} cancellation acknowledged")
     
-    def simple_speaker_detection(self, audio_path, segments, job_id=None):
+    def simple_speaker_detection(self, audio_path, segments, job_id=None, num_speakers=0):
         try:
             import librosa
             
@@ -207,7 +560,7 @@ class TranscriptionWorker:
                 else:
                     segment_features.append([0, 0, 0, 0])
             
-            n_speakers = min(4, len(segments))
+            n_speakers = min(num_speakers or 4, len(segments))
             if n_speakers > 1:
                 clustering = AgglomerativeClustering(n_clusters=n_speakers)
                 labels = clustering.fit_predict(segment_features)
@@ -230,7 +583,7 @@ class TranscriptionWorker:
             logger.error(f"simple speaker detection error: {e}")
             return segments
         
-    def resemblyzer_speaker_detection(self, audio_path, segments, job_id=None):
+    def resemblyzer_speaker_detection(self, audio_path, segments, job_id=None, num_speakers=0):
         try:
             logger.info("performing resemblyzer speaker detection...")
             if job_id: 
@@ -256,7 +609,7 @@ class TranscriptionWorker:
                         embeddings.append(np.zeros(256))
 
             embeddings = np.array(embeddings)
-            n_speakers = min(4, len(segments))
+            n_speakers = min(num_speakers or 4, len(segments))
 
             if n_speakers > 1:
                 clustering = AgglomerativeClustering(
@@ -281,7 +634,7 @@ class TranscriptionWorker:
             logger.error(f"resemblyzer detection error: {e}")
             return segments
         
-    def pyannote_speaker_detection(self, audio_path, segments, job_id=None):
+    def pyannote_speaker_detection(self, audio_path, segments, job_id=None, num_speakers=0):
         try:
             if not self.pyannote_pipeline:
                 return segments
@@ -291,7 +644,10 @@ class TranscriptionWorker:
                 self.check_cancellation(job_id)
                 self.publish_progress(job_id, "detecting_speakers")
 
-            diarization = self.pyannote_pipeline(audio_path)
+            if num_speakers:
+                diarization = self.pyannote_pipeline(audio_path, num_speakers=num_speakers)
+            else:
+                diarization = self.pyannote_pipeline(audio_path)
 
             for seg in segments:
                 seg_mid = (seg['start'] + seg['end']) / 2
@@ -313,7 +669,15 @@ class TranscriptionWorker:
             logger.error(f"pyannote detection error: {e}")
             return segments
 
-    def transcribe_audio(self, file_path, job_id):
+    def transcribe_audio(self, file_path, job_id, options=None):
+        options = options or {}
+        model = self.get_model(options.get('model') or MODEL_SIZE)
+        language = options.get('language') or None
+        task = options.get('task') or 'transcribe'
+        diarization_method = options.get('diarization') or DIARIZATION_METHOD
+        num_speakers = options.get('num_speakers') or 0
+        self.load_diarization(diarization_method)
+
         try:
             logger.info(f"transcribing: {file_path}")
             self.check_cancellation(job_id)
@@ -322,12 +686,9 @@ class TranscriptionWorker:
             self.check_cancellation(job_id)
             self.publish_progress(job_id, "transcribing")
             
-            self.check_cancellation(job_id)
-            self.publish_progress(job_id, "transcribing")
-            
             ctx = mp.get_context('spawn')
             queue = ctx.Queue()
-            p = ctx.Process(target=run_whisper_inference, args=(self.model, file_path, queue))
+            p = ctx.Process(target=run_whisper_inference, args=(model, file_path, queue, language, task))
             p.start()
             
             while p.is_alive():
@@ -351,7 +712,9 @@ class TranscriptionWorker:
                  raise Exception("transcription process failed or returned no result")
             
             full_text = result['text'].strip()
-            duration = result.get('duration', 0)
+            # whisper does not report a duration; the last segment's end is the
+            # closest value it gives
+            duration = result['segments'][-1]['end'] if result['segments'] else 0
 
             segments = []
             for i, segment in enumerate(result['segments']):
@@ -361,17 +724,32 @@ class TranscriptionWorker:
                     'end': round(segment['end'], 2),
                     'text': segment['text'].strip()
                 }
+
+                words = []
+                for word in segment.get('words', []):
+                    text = word['word'].strip()
+                    if not text:
+                        continue
+                    words.append({
+                        'text': text,
+                        'start': round(word['start'], 3),
+                        'end': round(word['end'], 3),
+                        'probability': round(float(word.get('probability', 0)), 4)
+                    })
+                if words:
+                    seg['words'] = words
+
                 segments.append(seg)
             
             logger.info(f"transcription completed: {len(segments)} segments")
             logger.info(f"duration: {self.format_timestamp(duration)}")
 
-            if DIARIZATION_METHOD == 'simple':
-                segments = self.simple_speaker_detection(file_path, segments, job_id)
-            elif DIARIZATION_METHOD == 'resemblyzer' and self.voice_encoder:
-                segments = self.resemblyzer_speaker_detection(file_path, segments, job_id)
-            elif DIARIZATION_METHOD == 'pyannote' and self.pyannote_pipeline:
-                segments = self.pyannote_speaker_detection(file_path, segments, job_id)
+            if diarization_method == 'simple':
+                segments = self.simple_speaker_detection(file_path, segments, job_id, num_speakers)
+            elif diarization_method == 'resemblyzer' and self.voice_encoder:
+                segments = self.resemblyzer_speaker_detection(file_path, segments, job_id, num_speakers)
+            elif diarization_method == 'pyannote' and self.pyannote_pipeline:
+                segments = self.pyannote_speaker_detection(file_path, segments, job_id, num_speakers)
             
             return full_text, segments, duration
             
@@ -379,24 +757,37 @@ class TranscriptionWorker:
             logger.error(f"transcription error: {e}")
             raise
 
-    def process_job(self, job_data):
+    def process_job(self, raw, job_data):
         job_id = job_data['job_id']
-        file_path = job_data['file_path']
         user_id = job_data['user_id']
+        options = job_data.get('options') or {}
 
         logger.info("=" * 60)
         logger.info(f"processing job: {job_id} (User: {user_id})")
         logger.info("=" * 60)
         
+        current_status = self.get_job_status(job_id)
+        if current_status == 'cancelling':
+            logger.warning(f"skipping job {job_id}: cancelled before it started")
+            self.acknowledge_cancellation(job_id)
+            return
+        if current_status in ('done', 'failed', 'cancelled'):
+            logger.warning(f"skipping job {job_id}: already {current_status}")
+            return
+
+        tmp_dir = None
+
         try:
-            if not os.path.exists(file_path):
-                raise FileNotFoundError(f"file not found: {file_path}")
-            
-            self.update_job_status(job_id, 'processing')
+            file_path, tmp_dir = self.fetch_input(job_data)
+
+            if not self.update_job_status(job_id, 'processing'):
+                if self.get_job_status(job_id) == 'cancelling':
+                    self.acknowledge_cancellation(job_id)
+                return
             
             start_time = time.time()
             start_time = time.time()
-            full_text, segments, duration = self.transcribe_audio(file_path, job_id)
+            full_text, segments, duration = self.transcribe_audio(file_path, job_id, options)
             process_time = time.time() - start_time
             process_time = time.time() - start_time
             
@@ -410,23 +801,35 @@ class TranscriptionWorker:
                 logger.info(f"  [{self.format_timestamp(seg['start'])} - {self.format_timestamp(seg['end'])}]{speaker_info}: {seg['text'][:50]}...")
 
             self.publish_progress(job_id, "saving")
-            self.update_job_status(
+            saved = self.update_job_status(
                 job_id, 'done', 
                 text=full_text, 
                 segments=segments,
                 duration=duration
             )
+
+            if not saved:
+                if self.get_job_status(job_id) == 'cancelling':
+                    self.acknowledge_cancellation(job_id)
+                return
             
             logger.info(f"job {job_id} completed successfully")
             
         except JobCancelledException as e:
-            logger.warning(f"job {job_id} cancelled execution")
-            self.update_job_status(job_id, 'cancelled', error_msg=str(e))
+            logger.warning(f"job {job_id} cancelled execution: {e}")
+            self.acknowledge_cancellation(job_id)
             
         except Exception as e:
             error_msg = str(e)
             logger.error(f"job {job_id} failed: {error_msg}")
-            self.update_job_status(job_id, 'failed', error_msg=error_msg)
+            if self.get_job_status(job_id) == 'cancelling':
+                self.acknowledge_cancellation(job_id)
+                return
+            self.handle_failure(raw, job_data, error_msg, retryable=not isinstance(e, NON_RETRYABLE_ERRORS))
+
+        finally:
+            if tmp_dir:
+                shutil.rmtree(tmp_dir, ignore_errors=True)
 
     def run(self):
         logger.info("=" * 60)
@@ -434,15 +837,20 @@ class TranscriptionWorker:
         logger.info(f"whisper model: {MODEL_SIZE}")
         logger.info(f"diarization method: {DIARIZATION_METHOD}")
         logger.info(f"queue: {REDIS_QUEUE}")
+        logger.info(f"worker id: {WORKER_ID}")
         logger.info("=" * 60)
         
         while True:
             try:
-                job = self.redis_client.blpop(REDIS_QUEUE, timeout=5)
-                
-                if job:
-                    job_data = json.loads(job[1])
-                    self.process_job(job_data)
+                raw, job_data = self.claim_job()
+
+                if job_data:
+                    lease = LeaseHeartbeat(self, raw, job_data['job_id']).start()
+                    try:
+                        self.process_job(raw, job_data)
+                    finally:
+                        lease.stop()
+                        self.ack_job(raw, job_data['job_id'])
                     
             except KeyboardInterrupt:
                 logger.info("\nshutting down worker...")